@@ -16,10 +16,26 @@ package gospel
 
 import (
 	"io/fs"
+	"time"
 )
 
 type App struct {
 	Root         func(Context) Element
 	StaticFiles  []fs.FS
 	StaticPrefix string
+	// maximum number of memoized values that are kept between requests
+	MemoCacheSize int
+	// state that is shared between all requests
+	State *AppState
+	// maximum time for rendering a page, 0 means no timeout. Pages that
+	// exceed it get a 503 response and their session changes are discarded.
+	RenderTimeout time.Duration
+	// service providers, see Provide
+	Services *Services
+	// rendered when a route guard denies access
+	Forbidden ElementFunction
+	// limits the requests to the whole app (except static files)
+	RateLimiter *RateLimiter
+	// pretty or minified output, see RenderWith
+	RenderOptions *RenderOptions
 }
@@ -0,0 +1,353 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"github.com/gospel-sh/gospel"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	sessionKey = "auth.session"
+	stateKey   = "auth.state"
+)
+
+// Loads a user by its ID, e.g. from the database
+type UserLoader[T any] func(c gospel.Context, id string) (T, error)
+
+type Manager[T any] struct {
+	Sessions SessionStore
+	Tokens   TokenStore
+	Load     UserLoader[T]
+	// where RequireAuth redirects to if there's no user
+	LoginPath      string
+	SessionTTL     time.Duration
+	RememberTTL    time.Duration
+	RememberCookie string
+}
+
+// Makes a manager that keeps sessions and tokens in memory
+func MakeManager[T any](load UserLoader[T]) *Manager[T] {
+
+	store := MakeInMemoryStore()
+
+	return &Manager[T]{
+		Sessions:       store,
+		Tokens:         store,
+		Load:           load,
+		LoginPath:      "/login",
+		SessionTTL:     24 * time.Hour,
+		RememberTTL:    30 * 24 * time.Hour,
+		RememberCookie: "remember",
+	}
+}
+
+// Makes the manager available to all requests of the app
+func (m *Manager[T]) Register(app *gospel.App) {
+	gospel.Provide(app, func(c gospel.Context) (*Manager[T], error) {
+		return m, nil
+	}, gospel.Singleton)
+
+	// the policy doesn't know the user type, so we provide the user as any
+	gospel.Provide(app, func(c gospel.Context) (UserFunc, error) {
+		return func(c gospel.Context) (any, bool) {
+			return m.User(c)
+		}, nil
+	}, gospel.Singleton)
+}
+
+func UseManager[T any](c gospel.Context) *Manager[T] {
+	return gospel.Use[*Manager[T]](c)
+}
+
+// the user of the current request, which we only load once
+type state[T any] struct {
+	loaded bool
+	user   T
+	userID string
+}
+
+func requestState[T any](c gospel.Context) *state[T] {
+
+	if variable := c.GetVar(stateKey); variable != nil {
+		if s, ok := variable.GetRaw().(*state[T]); ok {
+			return s
+		}
+	}
+
+	s := &state[T]{}
+	gospel.GlobalVar(c, stateKey, s)
+
+	return s
+}
+
+func sessionVar(c gospel.Context) *gospel.VarObj[string] {
+	return gospel.PersistentGlobalVar(c, sessionKey, "")
+}
+
+// Logs in the user with the given ID. We always create a new session so
+// that an attacker can't fixate the session ID before the login.
+func (m *Manager[T]) Login(c gospel.Context, userID string, remember bool) error {
+
+	session := sessionVar(c)
+
+	if oldID := session.Get(); oldID != "" {
+		if err := m.Sessions.DeleteSession(oldID); err != nil && err != NotFound {
+			return err
+		}
+	}
+
+	id, err := RandomToken(32)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	if err := m.Sessions.SaveSession(&Session{
+		ID:      id,
+		UserID:  userID,
+		Created: now,
+		Expires: now.Add(m.SessionTTL),
+	}); err != nil {
+		return err
+	}
+
+	if err := session.Set(id); err != nil {
+		return err
+	}
+
+	if remember {
+		if err := m.remember(c, userID); err != nil {
+			return err
+		}
+	}
+
+	// the user will be loaded again on the next access
+	*requestState[T](c) = state[T]{}
+
+	return nil
+}
+
+// Issues a new remember-me token and stores it in a cookie
+func (m *Manager[T]) remember(c gospel.Context, userID string) error {
+
+	selector, err := RandomToken(12)
+
+	if err != nil {
+		return err
+	}
+
+	validator, err := RandomToken(32)
+
+	if err != nil {
+		return err
+	}
+
+	hash := sha256.Sum256([]byte(validator))
+	expires := time.Now().Add(m.RememberTTL)
+
+	if err := m.Tokens.SaveToken(&Token{
+		Selector:      selector,
+		ValidatorHash: hash[:],
+		UserID:        userID,
+		Expires:       expires,
+	}); err != nil {
+		return err
+	}
+
+	m.setRememberCookie(c, selector+":"+validator, expires)
+
+	return nil
+}
+
+func (m *Manager[T]) setRememberCookie(c gospel.Context, value string, expires time.Time) {
+	http.SetCookie(c.ResponseWriter(), &http.Cookie{
+		Name:     m.RememberCookie,
+		Value:    value,
+		Path:     "/",
+		Expires:  expires,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// Ends the session and revokes the remember-me token of the request
+func (m *Manager[T]) Logout(c gospel.Context) error {
+
+	session := sessionVar(c)
+
+	if id := session.Get(); id != "" {
+		if err := m.Sessions.DeleteSession(id); err != nil && err != NotFound {
+			return err
+		}
+	}
+
+	if err := session.Set(""); err != nil {
+		return err
+	}
+
+	if cookie, err := c.Request().Cookie(m.RememberCookie); err == nil {
+		selector, _, _ := strings.Cut(cookie.Value, ":")
+		if err := m.Tokens.DeleteToken(selector); err != nil && err != NotFound {
+			return err
+		}
+		m.setRememberCookie(c, "", time.Unix(0, 0))
+	}
+
+	*requestState[T](c) = state[T]{loaded: true}
+
+	return nil
+}
+
+// Returns the ID of the logged in user, or an empty string
+func (m *Manager[T]) UserID(c gospel.Context) string {
+
+	if id := sessionVar(c).Get(); id != "" {
+		if session, err := m.Sessions.GetSession(id); err == nil {
+			if time.Now().Before(session.Expires) {
+				return session.UserID
+			}
+			m.Sessions.DeleteSession(id)
+		}
+	}
+
+	// we try to log the user in with the remember-me token
+	cookie, err := c.Request().Cookie(m.RememberCookie)
+
+	if err != nil {
+		return ""
+	}
+
+	selector, validator, ok := strings.Cut(cookie.Value, ":")
+
+	if !ok {
+		return ""
+	}
+
+	token, err := m.Tokens.GetToken(selector)
+
+	if err != nil {
+		return ""
+	}
+
+	hash := sha256.Sum256([]byte(validator))
+
+	if subtle.ConstantTimeCompare(hash[:], token.ValidatorHash) != 1 || time.Now().After(token.Expires) {
+		// a wrong validator means that the token might have been stolen
+		m.Tokens.DeleteToken(selector)
+		return ""
+	}
+
+	// tokens can only be used once, so we issue a new one with the login
+	m.Tokens.DeleteToken(selector)
+
+	if err := m.Login(c, token.UserID, true); err != nil {
+		gospel.Log.Error("Cannot log in with remember-me token: %v", err)
+		return ""
+	}
+
+	return token.UserID
+}
+
+// A key function for rate limiters that uses the ID of the logged in user.
+// Session IDs are checked against the session store, so clients can't
+// forge them to get a new bucket. Anonymous requests are limited by IP.
+func (m *Manager[T]) ByUser(c gospel.Context) string {
+
+	if id := m.UserID(c); id != "" {
+		return "user:" + id
+	}
+
+	return "ip:" + gospel.ByIP(c)
+}
+
+// Returns the logged in user
+func (m *Manager[T]) User(c gospel.Context) (T, bool) {
+
+	s := requestState[T](c)
+
+	if !s.loaded {
+
+		userID := m.UserID(c)
+		s.loaded = true
+
+		if userID != "" {
+			if user, err := m.Load(c, userID); err != nil {
+				gospel.Log.Error("Cannot load user '%s': %v", userID, err)
+			} else {
+				s.user, s.userID = user, userID
+			}
+		}
+	}
+
+	return s.user, s.userID != ""
+}
+
+// Returns the logged in user, using the manager registered with the app
+func CurrentUser[T any](c gospel.Context) (T, bool) {
+
+	m := UseManager[T](c)
+
+	if m == nil {
+		return *new(T), false
+	}
+
+	return m.User(c)
+}
+
+// Wraps an element function so that it redirects to the login page if
+// there's no user. The login page receives the original path as 'next'.
+func RequireAuth[T any](elementFunction gospel.ElementFunction) gospel.ElementFunction {
+	return func(c gospel.Context) gospel.Element {
+
+		if _, ok := CurrentUser[T](c); ok {
+			return elementFunction(c)
+		}
+
+		m := UseManager[T](c)
+		router := gospel.UseRouter(c)
+
+		if m == nil || router == nil {
+			gospel.Log.Error("Cannot redirect to login page")
+			c.SetStatusCode(http.StatusUnauthorized)
+			return nil
+		}
+
+		router.RedirectTo(fmt.Sprintf("%s?next=%s", m.LoginPath, url.QueryEscape(c.Request().URL.RequestURI())))
+
+		return nil
+	}
+}
+
+// Returns the 'next' parameter of the login page if it's a local path
+func NextPath(c gospel.Context, fallback string) string {
+
+	next := c.Request().URL.Query().Get("next")
+
+	// we don't redirect to other hosts
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return fallback
+	}
+
+	return next
+}
@@ -0,0 +1,297 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package auth
+
+import (
+	"github.com/gospel-sh/gospel"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type testUser struct {
+	Name  string
+	Admin bool
+}
+
+func (t *testUser) Roles() []string {
+	if t.Admin {
+		return []string{"admin"}
+	}
+	return nil
+}
+
+type document struct {
+	Owner string
+}
+
+func TestPasswords(t *testing.T) {
+
+	Iterations = 1000
+
+	hash, err := HashPassword("secret")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ok, err := CheckPassword(hash, "secret"); !ok || err != nil {
+		t.Fatalf("expected the password to match: %v", err)
+	}
+
+	if ok, _ := CheckPassword(hash, "wrong"); ok {
+		t.Fatalf("expected the password not to match")
+	}
+
+	// malformed hashes never match
+	salt := "c2FsdHNhbHRzYWx0c2FsdA"
+
+	for _, invalid := range []string{
+		"",
+		"md5$1000$" + salt + "$",
+		"pbkdf2-sha256$1000$" + salt,
+		"pbkdf2-sha256$1000$" + salt + "$",
+		"pbkdf2-sha256$1000$" + salt + "$AAAA",
+		"pbkdf2-sha256$0$" + salt + "$" + hash[len(hash)-43:],
+		"pbkdf2-sha256$999999999999$" + salt + "$" + hash[len(hash)-43:],
+		"pbkdf2-sha256$1000$%%%$" + hash[len(hash)-43:],
+	} {
+		if ok, err := CheckPassword(invalid, "secret"); ok || err == nil {
+			t.Fatalf("expected an error for '%s'", invalid)
+		}
+	}
+
+	// RFC 7914, section 11
+	key := pbkdf2([]byte("passwd"), []byte("salt"), 1, 64)
+
+	if expected := "55ac046e56e3089fec1691c22544b605"; gospel.Hex(key[:16]) != expected {
+		t.Fatalf("unexpected key: %s", gospel.Hex(key))
+	}
+}
+
+func makeTestContext(app *gospel.App, cookies ...*http.Cookie) (*gospel.DefaultContext, *httptest.ResponseRecorder) {
+
+	r := httptest.NewRequest("GET", "/admin", nil)
+
+	for _, cookie := range cookies {
+		r.AddCookie(cookie)
+	}
+
+	w := httptest.NewRecorder()
+	c := gospel.MakeDefaultContext(r, w, gospel.MakeStore(gospel.MakeCookieStore("")))
+	c.SetApp(app)
+	gospel.MakeRouter(c)
+
+	return c, w
+}
+
+func TestLogin(t *testing.T) {
+
+	app := &gospel.App{}
+
+	m := MakeManager(func(c gospel.Context, id string) (*testUser, error) {
+		return &testUser{Name: id}, nil
+	})
+
+	m.Register(app)
+
+	c, _ := makeTestContext(app)
+
+	admin := RequireAuth[*testUser](func(c gospel.Context) gospel.Element {
+		return gospel.Div("admin")
+	})
+
+	c.Execute(admin)
+
+	if redirectedTo := gospel.UseRouter(c).RedirectedTo(); redirectedTo != "/login?next=%2Fadmin" {
+		t.Fatalf("expected a redirect to the login page, got '%s'", redirectedTo)
+	}
+
+	c.Execute(func(c gospel.Context) gospel.Element {
+		sessionVar(c).Set("fixated")
+		return nil
+	})
+
+	if err := m.Login(c, "alice", true); err != nil {
+		t.Fatal(err)
+	}
+
+	var sessionID string
+
+	c.Execute(func(c gospel.Context) gospel.Element {
+		sessionID = sessionVar(c).Get()
+		return nil
+	})
+
+	if sessionID == "fixated" || sessionID == "" {
+		t.Fatalf("expected a new session ID")
+	}
+
+	if user, ok := CurrentUser[*testUser](c); !ok || user.Name != "alice" {
+		t.Fatalf("expected a user")
+	}
+}
+
+func TestRememberMe(t *testing.T) {
+
+	app := &gospel.App{}
+
+	m := MakeManager(func(c gospel.Context, id string) (*testUser, error) {
+		return &testUser{Name: id}, nil
+	})
+
+	m.Register(app)
+
+	c, w := makeTestContext(app)
+
+	if err := m.Login(c, "bob", true); err != nil {
+		t.Fatal(err)
+	}
+
+	cookies := w.Result().Cookies()
+
+	if len(cookies) != 1 || cookies[0].Name != "remember" {
+		t.Fatalf("expected a remember-me cookie")
+	}
+
+	// a new session without the session ID
+	c, w = makeTestContext(app, cookies[0])
+
+	if user, ok := CurrentUser[*testUser](c); !ok || user.Name != "bob" {
+		t.Fatalf("expected to be logged in with the token")
+	}
+
+	// the token was rotated, so it can't be used again
+	c, _ = makeTestContext(app, cookies[0])
+
+	if _, ok := CurrentUser[*testUser](c); ok {
+		t.Fatalf("expected the token to be invalid")
+	}
+
+	if len(w.Result().Cookies()) != 1 {
+		t.Fatalf("expected a new remember-me cookie")
+	}
+}
+
+func TestPolicy(t *testing.T) {
+
+	app := &gospel.App{}
+
+	m := MakeManager(func(c gospel.Context, id string) (*testUser, error) {
+		return &testUser{Name: id, Admin: id == "admin"}, nil
+	})
+
+	m.Register(app)
+
+	isOwner := func(c gospel.Context, user any, resource any) bool {
+		return resource.(*document).Owner == user.(*testUser).Name
+	}
+
+	MakePolicy().
+		Allow(Anonymous, "view", "document").
+		Allow(Authenticated, "edit", "document", isOwner).
+		Allow("admin", Any, Any).
+		Register(app)
+
+	doc := &document{Owner: "alice"}
+
+	for _, test := range []struct {
+		user    string
+		action  string
+		allowed bool
+	}{
+		{"", "view", true},
+		{"", "edit", false},
+		{"alice", "edit", true},
+		{"bob", "edit", false},
+		{"bob", "delete", false},
+		{"admin", "delete", true},
+	} {
+
+		c, _ := makeTestContext(app)
+
+		if test.user != "" {
+			if err := m.Login(c, test.user, false); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if Can(c, test.action, doc) != test.allowed {
+			t.Errorf("%s/%s: expected %v", test.user, test.action, test.allowed)
+		}
+	}
+
+	app.Forbidden = func(c gospel.Context) gospel.Element {
+		return gospel.Div("no access")
+	}
+
+	c, _ := makeTestContext(app)
+
+	element := c.Execute(func(c gospel.Context) gospel.Element {
+		return gospel.UseRouter(c).Match(c, gospel.Route("/admin", func(c gospel.Context) gospel.Element {
+			return gospel.Div("admin")
+		}).Guard(Allowed("view", "admin")))
+	})
+
+	if c.StatusCode() != 403 || element.RenderElement() != "<div>no access</div>" {
+		t.Fatalf("expected the route to be forbidden")
+	}
+}
+
+func TestRateLimitByUser(t *testing.T) {
+
+	app := &gospel.App{}
+
+	m := MakeManager(func(c gospel.Context, id string) (*testUser, error) {
+		return &testUser{Name: id}, nil
+	})
+
+	m.Register(app)
+
+	limiter := gospel.MakeRateLimiter("test", gospel.PerMinute(1, 1), m.ByUser)
+
+	// each request sends a different, forged session ID
+	for i, forged := range []string{"forged-1", "forged-2"} {
+
+		c, _ := makeTestContext(app)
+
+		c.Execute(func(c gospel.Context) gospel.Element {
+			sessionVar(c).Set(forged)
+			return nil
+		})
+
+		if key := m.ByUser(c); key != "ip:192.0.2.1" {
+			t.Fatalf("expected the IP address for a forged session, got '%s'", key)
+		}
+
+		if allowed, _ := limiter.Allow(c); allowed != (i == 0) {
+			t.Fatalf("expected forged session IDs to share the bucket of the IP address")
+		}
+	}
+
+	c, _ := makeTestContext(app)
+
+	if err := m.Login(c, "alice", false); err != nil {
+		t.Fatal(err)
+	}
+
+	if key := m.ByUser(c); key != "user:alice" {
+		t.Fatalf("expected the user ID, got '%s'", key)
+	}
+
+	if allowed, _ := limiter.Allow(c); !allowed {
+		t.Fatalf("expected the user to have their own bucket")
+	}
+}
@@ -0,0 +1,143 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The number of PBKDF2 iterations for new hashes, existing hashes store
+// their own iteration count
+var Iterations = 600000
+
+// Hashes with more iterations are rejected, so that a crafted hash can't
+// keep the CPU busy
+var MaxIterations = 10000000
+
+const (
+	hashScheme = "pbkdf2-sha256"
+	saltLength = 16
+	keyLength  = 32
+)
+
+// PBKDF2 (RFC 8018) with HMAC-SHA256
+func pbkdf2(password, salt []byte, iterations, length int) []byte {
+
+	prf := hmac.New(sha256.New, password)
+	key := make([]byte, 0, length)
+	block := make([]byte, 4)
+
+	for i := uint32(1); len(key) < length; i++ {
+
+		prf.Reset()
+		prf.Write(salt)
+		binary.BigEndian.PutUint32(block, i)
+		prf.Write(block)
+
+		u := prf.Sum(nil)
+		t := make([]byte, len(u))
+		copy(t, u)
+
+		for j := 1; j < iterations; j++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for k := range t {
+				t[k] ^= u[k]
+			}
+		}
+
+		key = append(key, t...)
+	}
+
+	return key[:length]
+}
+
+// Hashes a password with a random salt. The result contains the scheme,
+// iteration count and salt, so it can be stored as is.
+func HashPassword(password string) (string, error) {
+
+	salt := make([]byte, saltLength)
+
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := pbkdf2([]byte(password), salt, Iterations, keyLength)
+
+	return fmt.Sprintf("%s$%d$%s$%s",
+		hashScheme,
+		Iterations,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Checks a password against a hash created by HashPassword
+func CheckPassword(hash, password string) (bool, error) {
+
+	parts := strings.Split(hash, "$")
+
+	if len(parts) != 4 || parts[0] != hashScheme {
+		return false, fmt.Errorf("invalid hash")
+	}
+
+	iterations, err := strconv.Atoi(parts[1])
+
+	if err != nil || iterations < 1 || iterations > MaxIterations {
+		return false, fmt.Errorf("invalid iteration count")
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+
+	if err != nil {
+		return false, fmt.Errorf("invalid salt: %w", err)
+	}
+
+	expected, err := base64.RawStdEncoding.DecodeString(parts[3])
+
+	if err != nil {
+		return false, fmt.Errorf("invalid key: %w", err)
+	}
+
+	// an empty key would match any password
+	if len(expected) != keyLength {
+		return false, fmt.Errorf("invalid key length %d", len(expected))
+	}
+
+	key := pbkdf2([]byte(password), salt, iterations, len(expected))
+
+	return subtle.ConstantTimeCompare(key, expected) == 1, nil
+}
+
+// Returns a random, URL-safe token with the given number of bytes
+func RandomToken(n int) (string, error) {
+
+	data := make([]byte, n)
+
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
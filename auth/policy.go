@@ -0,0 +1,209 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package auth
+
+import (
+	"github.com/gospel-sh/gospel"
+	"reflect"
+	"sync"
+)
+
+const (
+	// the role of requests without a user
+	Anonymous = "anonymous"
+	// the role of all logged in users
+	Authenticated = "authenticated"
+	// matches any action or resource
+	Any = "*"
+)
+
+// Implemented by users that have roles
+type RoleProvider interface {
+	Roles() []string
+}
+
+// Implemented by resources to define the name they're referred to in the
+// policy. Other resources use the name of their type (e.g. "Document").
+type Resource interface {
+	ResourceName() string
+}
+
+// Returns the user of the current request, see Manager.Register
+type UserFunc func(c gospel.Context) (any, bool)
+
+// An additional condition for a permission, e.g. that the user owns
+// the resource. The user is nil for anonymous requests.
+type Rule func(c gospel.Context, user any, resource any) bool
+
+type grant struct {
+	resource string
+	rules    []Rule
+}
+
+// Declares which roles may perform which actions on which resources
+type Policy struct {
+	mutex  sync.RWMutex
+	grants map[string]map[string][]grant
+}
+
+func MakePolicy() *Policy {
+	return &Policy{
+		grants: make(map[string]map[string][]grant),
+	}
+}
+
+// Makes the policy available to all requests of the app
+func (p *Policy) Register(app *gospel.App) {
+	gospel.Provide(app, func(c gospel.Context) (*Policy, error) {
+		return p, nil
+	}, gospel.Singleton)
+}
+
+// Allows the role to perform the action on the resource if all rules pass.
+// The action and resource can be Any.
+func (p *Policy) Allow(role, action, resource string, rules ...Rule) *Policy {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	actions, ok := p.grants[role]
+
+	if !ok {
+		actions = make(map[string][]grant)
+		p.grants[role] = actions
+	}
+
+	actions[action] = append(actions[action], grant{resource, rules})
+
+	return p
+}
+
+// Returns the name of the resource in the policy
+func ResourceName(resource any) string {
+
+	switch r := resource.(type) {
+	case nil:
+		return ""
+	case string:
+		return r
+	case Resource:
+		return r.ResourceName()
+	}
+
+	t := reflect.TypeOf(resource)
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t.Name()
+}
+
+// Returns the roles of a user, including Anonymous or Authenticated
+func Roles(user any, ok bool) []string {
+
+	if !ok {
+		return []string{Anonymous}
+	}
+
+	roles := []string{Authenticated}
+
+	if roleProvider, ok := user.(RoleProvider); ok {
+		roles = append(roles, roleProvider.Roles()...)
+	}
+
+	return roles
+}
+
+// Checks whether a user with the given roles may perform the action
+func (p *Policy) Allowed(c gospel.Context, user any, roles []string, action string, resource any) bool {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	name := ResourceName(resource)
+
+	for _, role := range roles {
+		for _, grantedAction := range []string{action, Any} {
+		grants:
+			for _, g := range p.grants[role][grantedAction] {
+
+				if g.resource != Any && g.resource != name {
+					continue
+				}
+
+				for _, rule := range g.rules {
+					if !rule(c, user, resource) {
+						continue grants
+					}
+				}
+
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Checks whether the user of the current request may perform the action
+// on the resource, using the policy registered with the app
+func Can(c gospel.Context, action string, resource any) bool {
+
+	policy, err := gospel.Resolve[*Policy](c)
+
+	if err != nil {
+		gospel.Log.Error("Cannot check permission: %v", err)
+		return false
+	}
+
+	var user any
+	var ok bool
+
+	if userFunc, err := gospel.Resolve[UserFunc](c); err == nil {
+		user, ok = userFunc(c)
+	}
+
+	if !ok {
+		user = nil
+	}
+
+	return policy.Allowed(c, user, Roles(user, ok), action, resource)
+}
+
+// Returns the children only if the action is allowed
+func IfCan(c gospel.Context, action string, resource any, children ...any) gospel.Element {
+	if Can(c, action, resource) {
+		return gospel.F(children...)
+	}
+	return nil
+}
+
+// Returns a route guard that checks a permission, e.g.
+// Route("/admin", admin).Guard(auth.Allowed("view", "admin"))
+func Allowed(action string, resource any) gospel.RouteGuard {
+	return func(c gospel.Context) bool {
+		return Can(c, action, resource)
+	}
+}
+
+// Wraps an element function so that it responds with 403 if the action
+// isn't allowed
+func RequirePermission(action string, resource any, elementFunction gospel.ElementFunction) gospel.ElementFunction {
+	return func(c gospel.Context) gospel.Element {
+		if !Can(c, action, resource) {
+			return gospel.Forbidden(c)
+		}
+		return elementFunction(c)
+	}
+}
@@ -0,0 +1,116 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package auth
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+var NotFound = fmt.Errorf("not found")
+
+type Session struct {
+	ID      string
+	UserID  string
+	Created time.Time
+	Expires time.Time
+}
+
+// A remember-me token. We only store a hash of the validator, so tokens
+// can't be used if the store is leaked.
+type Token struct {
+	Selector      string
+	ValidatorHash []byte
+	UserID        string
+	Expires       time.Time
+}
+
+type SessionStore interface {
+	GetSession(id string) (*Session, error)
+	SaveSession(session *Session) error
+	DeleteSession(id string) error
+}
+
+type TokenStore interface {
+	GetToken(selector string) (*Token, error)
+	SaveToken(token *Token) error
+	DeleteToken(selector string) error
+}
+
+// Stores sessions and tokens in memory, which is mostly useful for
+// development and tests as everything is lost on restart
+type InMemoryStore struct {
+	mutex    sync.Mutex
+	sessions map[string]*Session
+	tokens   map[string]*Token
+}
+
+func MakeInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{
+		sessions: make(map[string]*Session),
+		tokens:   make(map[string]*Token),
+	}
+}
+
+func (i *InMemoryStore) GetSession(id string) (*Session, error) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if session, ok := i.sessions[id]; ok {
+		return session, nil
+	}
+
+	return nil, NotFound
+}
+
+func (i *InMemoryStore) SaveSession(session *Session) error {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	i.sessions[session.ID] = session
+	return nil
+}
+
+func (i *InMemoryStore) DeleteSession(id string) error {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	delete(i.sessions, id)
+	return nil
+}
+
+func (i *InMemoryStore) GetToken(selector string) (*Token, error) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if token, ok := i.tokens[selector]; ok {
+		return token, nil
+	}
+
+	return nil, NotFound
+}
+
+func (i *InMemoryStore) SaveToken(token *Token) error {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	i.tokens[token.Selector] = token
+	return nil
+}
+
+func (i *InMemoryStore) DeleteToken(selector string) error {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	delete(i.tokens, selector)
+	return nil
+}
@@ -0,0 +1,287 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	. "github.com/gospel-sh/gospel"
+	"go/format"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// event handlers with hand-written attribute functions. `OnSubmit` isn't
+// one of them, as forms expect a server-side function for it.
+var eventAttributes = map[string]string{
+	"onclick":  "OnClick",
+	"onchange": "OnChange",
+}
+
+// the content of these tags is converted verbatim
+var preformattedTags = map[string]bool{
+	"pre": true, "textarea": true, "script": true, "style": true,
+}
+
+var whitespaceRegexp = regexp.MustCompile(`\s*\n\s*`)
+
+type converter struct {
+	b bytes.Buffer
+	// whether the code contains styles, which only apply within `Styled`
+	styled bool
+}
+
+func (c *converter) nodes(nodes []any, preformatted bool) {
+	for _, node := range nodes {
+		if element, ok := node.(*HTMLElement); ok && c.node(element, preformatted) {
+			c.b.WriteString(",\n")
+		}
+	}
+}
+
+// Returns the text of a literal element, unless it only formats the markup
+func text(element *HTMLElement, preformatted bool) (string, bool) {
+
+	text, ok := element.Value.(string)
+
+	if !ok || preformatted {
+		return text, ok
+	}
+
+	if strings.TrimSpace(text) == "" && strings.Contains(text, "\n") {
+		return "", false
+	}
+
+	return whitespaceRegexp.ReplaceAllString(text, " "), true
+}
+
+// Writes the expression of the element, returns false if it was dropped
+func (c *converter) node(element *HTMLElement, preformatted bool) bool {
+
+	if _, ok := element.Value.(string); ok {
+
+		if text, ok := text(element, preformatted); ok {
+			fmt.Fprintf(&c.b, "%q", text)
+			return true
+		}
+
+		return false
+	}
+
+	if info, ok := TagCatalog[element.Tag]; ok {
+		c.b.WriteString(info.GoName)
+	} else if element.Void {
+		fmt.Fprintf(&c.b, "VoidTag(%q)", element.Tag)
+	} else {
+		fmt.Fprintf(&c.b, "Tag(%q)", element.Tag)
+	}
+
+	preformatted = preformatted || preformattedTags[element.Tag]
+
+	if len(element.Attributes) == 0 {
+
+		if len(element.Children) == 0 {
+			c.b.WriteString("()")
+			return true
+		}
+
+		// elements with only text stay on one line, e.g. `B("bold")`
+		if child, ok := element.Children[0].(*HTMLElement); ok && len(element.Children) == 1 {
+			if text, ok := text(child, preformatted); ok {
+				fmt.Fprintf(&c.b, "(%q)", text)
+				return true
+			}
+		}
+	}
+
+	c.b.WriteString("(\n")
+
+	for _, attribute := range element.Attributes {
+		c.attribute(attribute)
+	}
+
+	c.nodes(element.Children, preformatted)
+	c.b.WriteString(")")
+
+	return true
+}
+
+func (c *converter) attribute(attribute *HTMLAttribute) {
+
+	name := attribute.Name
+	value, hasValue := attribute.Value.(string)
+
+	if name == "style" && hasValue {
+		c.styles(value)
+		return
+	}
+
+	// client-side handlers would be escaped as plain strings otherwise
+	if strings.HasPrefix(name, "on") && hasValue {
+		if goName, ok := eventAttributes[name]; ok {
+			fmt.Fprintf(&c.b, "%s(SafeJS(%q)),\n", goName, value)
+		} else {
+			fmt.Fprintf(&c.b, "Attrib(%q)(SafeJS(%q)),\n", name, value)
+		}
+		return
+	}
+
+	if info, ok := AttributeCatalog[name]; ok {
+		if info.Boolean {
+			fmt.Fprintf(&c.b, "%s(),\n", info.GoName)
+		} else {
+			fmt.Fprintf(&c.b, "%s(%q),\n", info.GoName, value)
+		}
+		return
+	}
+
+	// attributes without a function of their own
+	switch {
+	case strings.HasPrefix(name, "data-"):
+		fmt.Fprintf(&c.b, "DataAttrib(%q, %q),\n", name[5:], value)
+	case !hasValue:
+		fmt.Fprintf(&c.b, "BooleanAttrib(%q)(),\n", name)
+	default:
+		fmt.Fprintf(&c.b, "Attrib(%q)(%q),\n", name, value)
+	}
+}
+
+// Converts an inline style attribute to a list of declarations
+func (c *converter) styles(style string) {
+
+	c.styled = true
+	c.b.WriteString("Styles(\n")
+
+	for _, declaration := range strings.Split(style, ";") {
+
+		property, value, ok := strings.Cut(declaration, ":")
+
+		if !ok {
+			continue
+		}
+
+		property = strings.ToLower(strings.TrimSpace(property))
+		value = strings.TrimSpace(value)
+
+		if property == "" {
+			continue
+		}
+
+		// properties without a function of their own are declared with `Dec`
+		if goName, ok := CSSPropertyCatalog[property]; ok {
+			fmt.Fprintf(&c.b, "%s(%q),\n", goName, value)
+		} else {
+			fmt.Fprintf(&c.b, "Dec(%q)(%q),\n", property, value)
+		}
+	}
+
+	c.b.WriteString("),\n")
+}
+
+// Converts HTML to a Go file with a function that returns the element
+func convertHTML(source, packageName, funcName string) ([]byte, error) {
+
+	parser := &Parser{}
+	nodes, err := parser.ParseHTML(source)
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse HTML: %w", err)
+	}
+
+	c := &converter{}
+
+	var elements []*HTMLElement
+
+	for _, node := range nodes {
+		if element, ok := node.(*HTMLElement); ok {
+			if _, ok := text(element, false); ok || element.Value == nil {
+				elements = append(elements, element)
+			}
+		}
+	}
+
+	if len(elements) == 1 && elements[0].Value == nil {
+		c.node(elements[0], false)
+	} else {
+		// several top-level elements are returned as a fragment
+		c.b.WriteString("F(\n")
+		c.nodes(nodes, false)
+		c.b.WriteString(")")
+	}
+
+	element := c.b.String()
+
+	if c.styled {
+		// the styles are added to a stylesheet named after the function
+		element = fmt.Sprintf("Styled(%q, %s)", strings.ToLower(funcName), element)
+	}
+
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "package %s\n\n", packageName)
+	fmt.Fprintf(&b, "import (\n. \"github.com/gospel-sh/gospel\"\n)\n\n")
+	fmt.Fprintf(&b, "func %s() Element {\nreturn %s\n}\n", funcName, element)
+
+	return format.Source(b.Bytes())
+}
+
+// Converts an HTML file (or stdin) to Gospel code
+func convert(args []string) error {
+
+	flags := flag.NewFlagSet("convert", flag.ExitOnError)
+	packageName := flags.String("package", "main", "the package of the generated code")
+	funcName := flags.String("func", "Page", "the name of the generated function")
+	out := flags.String("out", "", "the output file (default stdout)")
+
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: gospel convert [flags] [file.html]\n")
+		flags.PrintDefaults()
+	}
+
+	flags.Parse(args)
+
+	var source []byte
+	var err error
+
+	switch flags.NArg() {
+	case 0:
+		source, err = io.ReadAll(os.Stdin)
+	case 1:
+		source, err = os.ReadFile(flags.Arg(0))
+	default:
+		flags.Usage()
+		return fmt.Errorf("expected at most one file")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	code, err := convertHTML(string(source), *packageName, *funcName)
+
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		_, err = os.Stdout.Write(code)
+		return err
+	}
+
+	return os.WriteFile(*out, code, 0644)
+}
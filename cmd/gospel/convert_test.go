@@ -0,0 +1,133 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type convertTest struct {
+	html string
+	// snippets of the generated code
+	code []string
+	// the output of the generated function
+	rendered string
+}
+
+var convertTests = []convertTest{
+	// styles
+	{
+		`<div class="box" style="background-color: red; grid-area: a">hi</div>`,
+		[]string{`Styled("case0", Div(`, `BackgroundColor("red")`, `Dec("grid-area")("a")`},
+		"<style>.case0-1 {\n  background-color: red;\n  grid-area: a;\n}\n\n</style><div class=\"box case0-1\">hi</div>",
+	},
+	// unknown, data and boolean attributes
+	{
+		`<input type="checkbox" checked data-id="3" x-foo="bar" x-flag>`,
+		[]string{`Type("checkbox")`, `CheckedAttr()`, `DataAttrib("id", "3")`, `Attrib("x-foo")("bar")`, `BooleanAttrib("x-flag")()`},
+		`<input type="checkbox" checked data-id="3" x-foo="bar" x-flag/>`,
+	},
+	// unknown tags
+	{
+		`<custom-el>hi</custom-el>`,
+		[]string{`Tag("custom-el")("hi")`},
+		`<custom-el>hi</custom-el>`,
+	},
+	// event handlers
+	{
+		`<form onsubmit="return false"><button onclick="doIt()">x</button></form>`,
+		[]string{`Attrib("onsubmit")(SafeJS("return false"))`, `OnClick(SafeJS("doIt()"))`},
+		`<form onsubmit="return false"><button onClick="doIt()">x</button></form>`,
+	},
+	// whitespace
+	{
+		"<p>\n  Hello <b>world</b>,\n  how are you?\n</p>",
+		[]string{`" Hello "`, `B("world")`, `", how are you? "`},
+		`<p> Hello <b>world</b>, how are you? </p>`,
+	},
+	// preformatted content
+	{
+		"<pre>a\n  b</pre><script>if (a < b) {}</script>",
+		[]string{`Pre("a\n  b")`, `Script("if (a < b) {}")`},
+		"<pre>a\n  b</pre><script>if (a < b) {}</script>",
+	},
+	// several top-level nodes
+	{
+		"<h1>Title</h1>\n<p>text</p>",
+		[]string{"F(\n", `H1("Title")`, `P("text")`},
+		`<h1>Title</h1><p>text</p>`,
+	},
+}
+
+func TestConvert(t *testing.T) {
+
+	// we generate a program that prints the output of all functions
+	dir, err := os.MkdirTemp(".", "converted")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	calls := []string{}
+
+	for i, test := range convertTests {
+
+		funcName := fmt.Sprintf("Case%d", i)
+		code, err := convertHTML(test.html, "main", funcName)
+
+		if err != nil {
+			t.Fatalf("cannot convert %q: %v", test.html, err)
+		}
+
+		for _, snippet := range test.code {
+			if !strings.Contains(string(code), snippet) {
+				t.Errorf("expected %q in the code for %q:\n%s", snippet, test.html, code)
+			}
+		}
+
+		if err := os.WriteFile(filepath.Join(dir, strings.ToLower(funcName)+".go"), code, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		calls = append(calls, fmt.Sprintf("fmt.Print(%s().RenderElement(), \"\\x00\")", funcName))
+	}
+
+	program := fmt.Sprintf("package main\n\nimport (\n\t\"fmt\"\n\t. \"github.com/gospel-sh/gospel\"\n)\n\nvar _ Element\n\nfunc main() {\n\t%s\n}\n", strings.Join(calls, "\n\t"))
+
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(program), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := exec.Command("go", "run", "./"+dir).CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("cannot run the generated code: %v\n%s", err, output)
+	}
+
+	rendered := strings.Split(string(output), "\x00")
+
+	for i, test := range convertTests {
+		if rendered[i] != test.rendered {
+			t.Errorf("unexpected output for %q:\n%s\nexpected:\n%s", test.html, rendered[i], test.rendered)
+		}
+	}
+}
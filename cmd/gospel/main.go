@@ -16,6 +16,7 @@ package main
 
 import (
 	"embed"
+	"fmt"
 	. "github.com/gospel-sh/gospel"
 	"github.com/gospel-sh/gospel/examples"
 	"io/fs"
@@ -70,6 +71,15 @@ func makeExamples() *Server {
 }
 
 func main() {
+
+	if len(os.Args) > 1 && os.Args[1] == "convert" {
+		if err := convert(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Cannot convert HTML: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	examplesServer := makeExamples()
 	examplesServer.Start()
 
@@ -0,0 +1,346 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+// Generates the HTML tag and attribute catalog of Gospel from the
+// specification in spec.go. It runs via `go generate` in the root package
+// and names tags and attributes after their HTML names (e.g. `accept-charset`
+// becomes `AcceptCharset`). If a name clashes with another identifier of the
+// package, tags get a `Tag` and attributes an `Attr` suffix (e.g. `StyleTag`).
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const header = `// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+// Code generated by cmd/htmlgen. DO NOT EDIT.
+
+`
+
+// Returns the names of all package-level identifiers of the package in
+// the given directory, except for those in the excluded (generated) file,
+// as well as the CSS properties that are declared with `Dec`
+func packageIdentifiers(dir, exclude string) (map[string]bool, map[string]string, error) {
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	identifiers := map[string]bool{}
+	properties := map[string]string{}
+	fset := token.NewFileSet()
+
+	for _, file := range files {
+
+		if filepath.Base(file) == exclude {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, file, nil, parser.SkipObjectResolution)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				// methods don't clash with package-level identifiers
+				if d.Recv == nil {
+					identifiers[d.Name.Name] = true
+				}
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					switch s := spec.(type) {
+					case *ast.ValueSpec:
+						for _, name := range s.Names {
+							identifiers[name.Name] = true
+						}
+						if property, ok := declaredProperty(s); ok {
+							properties[property] = s.Names[0].Name
+						}
+					case *ast.TypeSpec:
+						identifiers[s.Name.Name] = true
+					}
+				}
+			}
+		}
+	}
+
+	return identifiers, properties, nil
+}
+
+// Returns the property of a declaration like `var Color = Dec("color")`
+func declaredProperty(s *ast.ValueSpec) (string, bool) {
+
+	if len(s.Names) != 1 || len(s.Values) != 1 {
+		return "", false
+	}
+
+	call, ok := s.Values[0].(*ast.CallExpr)
+
+	if !ok || len(call.Args) != 1 {
+		return "", false
+	}
+
+	if fun, ok := call.Fun.(*ast.Ident); !ok || fun.Name != "Dec" {
+		return "", false
+	}
+
+	lit, ok := call.Args[0].(*ast.BasicLit)
+
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+
+	property, err := strconv.Unquote(lit.Value)
+
+	// we skip the universal selector ('Anything')
+	if err != nil || property == "*" {
+		return "", false
+	}
+
+	return property, true
+}
+
+func goName(name string) string {
+	parts := strings.Split(name, "-")
+	for i, part := range parts {
+		parts[i] = strings.ToUpper(part[:1]) + part[1:]
+	}
+	return strings.Join(parts, "")
+}
+
+type namer struct {
+	reserved map[string]bool
+	used     map[string]string
+}
+
+// Returns the Go name for the given tag or attribute
+func (n *namer) name(htmlName, explicitName, suffix string) (string, error) {
+
+	name := explicitName
+
+	if name == "" {
+		name = goName(htmlName)
+		if n.reserved[name] || n.used[name] != "" {
+			name += suffix
+		}
+	}
+
+	if n.reserved[name] {
+		return "", fmt.Errorf("'%s' clashes with an identifier of the package", name)
+	}
+
+	if other := n.used[name]; other != "" {
+		return "", fmt.Errorf("'%s' is used for both '%s' and '%s'", name, other, htmlName)
+	}
+
+	n.used[name] = htmlName
+
+	return name, nil
+}
+
+func quoted(values []string) string {
+	q := make([]string, len(values))
+	for i, value := range values {
+		q[i] = fmt.Sprintf("%q", value)
+	}
+	return strings.Join(q, ", ")
+}
+
+func elementList(elements []string) string {
+	l := make([]string, len(elements))
+	for i, element := range elements {
+		l[i] = "<" + element + ">"
+	}
+	return strings.Join(l, ", ")
+}
+
+func generate(reserved map[string]bool, properties map[string]string) ([]byte, error) {
+
+	n := &namer{
+		reserved: reserved,
+		used:     map[string]string{},
+	}
+
+	var b bytes.Buffer
+
+	b.WriteString(header)
+	b.WriteString("package gospel\n\n")
+
+	b.WriteString("// HTML Tags\n// https://html.spec.whatwg.org/multipage/indices.html#elements-3\n\n")
+
+	tagNames := map[string]string{}
+
+	for _, t := range tags {
+
+		name, err := n.name(t.Name, t.GoName, "Tag")
+
+		if err != nil {
+			return nil, err
+		}
+
+		tagNames[t.Name] = name
+
+		args := append([]string{fmt.Sprintf("%q", t.Name)}, t.Decorators...)
+		constructor := "Tag"
+
+		if t.Void {
+			constructor = "VoidTag"
+		}
+
+		if t.Obsolete {
+			fmt.Fprintf(&b, "// Deprecated: <%s> is obsolete.\n", t.Name)
+		}
+
+		fmt.Fprintf(&b, "var %s = %s(%s)\n", name, constructor, strings.Join(args, ", "))
+	}
+
+	b.WriteString("\n// HTML Attributes\n// https://html.spec.whatwg.org/multipage/indices.html#attributes-3\n\n")
+
+	attributeNames := map[string]string{}
+
+	for _, a := range attributes {
+
+		name, err := n.name(a.Name, a.GoName, "Attr")
+
+		if err != nil {
+			return nil, err
+		}
+
+		attributeNames[a.Name] = name
+
+		if a.Elements == nil {
+			fmt.Fprintf(&b, "// Global attribute\n")
+		} else {
+			fmt.Fprintf(&b, "// Attribute of %s\n", elementList(a.Elements))
+		}
+
+		switch {
+		case a.Boolean:
+			fmt.Fprintf(&b, "var %s = BooleanAttrib(%q)\n", name, a.Name)
+		case a.Values != nil:
+			fmt.Fprintf(&b, "var %s = EnumeratedAttrib(%q, %s)\n", name, a.Name, quoted(a.Values))
+		default:
+			fmt.Fprintf(&b, "var %s = Attrib(%q)\n", name, a.Name)
+		}
+	}
+
+	b.WriteString("\n// All tags by their HTML name\nvar TagCatalog = map[string]TagInfo{\n")
+
+	sortedTags := make([]tag, len(tags))
+	copy(sortedTags, tags)
+	sort.Slice(sortedTags, func(i, j int) bool { return sortedTags[i].Name < sortedTags[j].Name })
+
+	for _, t := range sortedTags {
+		fmt.Fprintf(&b, "%q: {GoName: %q, Void: %v},\n", t.Name, tagNames[t.Name], t.Void)
+	}
+
+	b.WriteString("}\n\n// All attributes by their HTML name\nvar AttributeCatalog = map[string]AttributeInfo{\n")
+
+	sortedAttributes := make([]attribute, len(attributes))
+	copy(sortedAttributes, attributes)
+	sort.Slice(sortedAttributes, func(i, j int) bool { return sortedAttributes[i].Name < sortedAttributes[j].Name })
+
+	for _, a := range sortedAttributes {
+
+		fields := []string{fmt.Sprintf("GoName: %q", attributeNames[a.Name])}
+
+		if a.Boolean {
+			fields = append(fields, "Boolean: true")
+		}
+
+		if a.Values != nil {
+			fields = append(fields, fmt.Sprintf("Values: []string{%s}", quoted(a.Values)))
+		}
+
+		if a.Elements != nil {
+			fields = append(fields, fmt.Sprintf("Elements: []string{%s}", quoted(a.Elements)))
+		}
+
+		fmt.Fprintf(&b, "%q: {%s},\n", a.Name, strings.Join(fields, ", "))
+	}
+
+	b.WriteString("}\n\n// CSS properties with a declaration function (see css.go) by their name\nvar CSSPropertyCatalog = map[string]string{\n")
+
+	sortedProperties := make([]string, 0, len(properties))
+
+	for property := range properties {
+		sortedProperties = append(sortedProperties, property)
+	}
+
+	sort.Strings(sortedProperties)
+
+	for _, property := range sortedProperties {
+		fmt.Fprintf(&b, "%q: %q,\n", property, properties[property])
+	}
+
+	b.WriteString("}\n")
+
+	return format.Source(b.Bytes())
+}
+
+func main() {
+
+	dir := flag.String("dir", ".", "the directory of the gospel package")
+	out := flag.String("out", "html_catalog.go", "the name of the generated file")
+
+	flag.Parse()
+
+	reserved, properties, err := packageIdentifiers(*dir, *out)
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot parse package: %v\n", err)
+		os.Exit(1)
+	}
+
+	source, err := generate(reserved, properties)
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot generate catalog: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(filepath.Join(*dir, *out), source, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot write catalog: %v\n", err)
+		os.Exit(1)
+	}
+}
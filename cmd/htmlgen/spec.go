@@ -0,0 +1,314 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package main
+
+// An HTML element of the living standard
+// https://html.spec.whatwg.org/multipage/indices.html#elements-3
+type tag struct {
+	Name string
+	Void bool
+	// Go expressions of the decorators of the tag
+	Decorators []string
+	// the name of the Go variable, if it deviates from the convention
+	GoName string
+	// the tag is obsolete but kept for backwards compatibility
+	Obsolete bool
+}
+
+// An HTML attribute of the living standard
+// https://html.spec.whatwg.org/multipage/indices.html#attributes-3
+type attribute struct {
+	Name    string
+	Boolean bool
+	// allowed values of enumerated attributes
+	Values []string
+	// the elements the attribute applies to (nil for global attributes)
+	Elements []string
+	GoName   string
+}
+
+var tags = []tag{
+	// document metadata
+	{Name: "html"},
+	{Name: "head"},
+	{Name: "title"},
+	{Name: "base", Void: true},
+	{Name: "link", Void: true},
+	{Name: "meta", Void: true},
+	// renamed as it clashes with the 'Style' attribute
+	{Name: "style", GoName: "StyleTag", Decorators: []string{"isScript"}},
+	// sections
+	{Name: "body"},
+	{Name: "article"},
+	{Name: "section"},
+	{Name: "nav"},
+	{Name: "aside"},
+	{Name: "h1"},
+	{Name: "h2"},
+	{Name: "h3"},
+	{Name: "h4"},
+	{Name: "h5"},
+	{Name: "h6"},
+	{Name: "hgroup"},
+	{Name: "header"},
+	{Name: "footer"},
+	{Name: "address"},
+	// grouping content
+	{Name: "p"},
+	{Name: "hr", Void: true},
+	{Name: "pre"},
+	{Name: "blockquote"},
+	{Name: "ol"},
+	{Name: "ul"},
+	{Name: "menu"},
+	{Name: "li"},
+	{Name: "dl"},
+	{Name: "dt"},
+	{Name: "dd"},
+	{Name: "figure"},
+	{Name: "figcaption"},
+	{Name: "main"},
+	{Name: "search"},
+	{Name: "div"},
+	// text-level semantics
+	{Name: "a", Decorators: []string{"Clickable()"}},
+	{Name: "em"},
+	{Name: "strong"},
+	{Name: "small"},
+	{Name: "s"},
+	{Name: "cite"},
+	{Name: "q"},
+	{Name: "dfn"},
+	{Name: "abbr"},
+	{Name: "ruby"},
+	{Name: "rt"},
+	{Name: "rp"},
+	{Name: "data"},
+	{Name: "time"},
+	{Name: "code"},
+	{Name: "var"},
+	{Name: "samp"},
+	{Name: "kbd"},
+	{Name: "sub"},
+	{Name: "sup"},
+	{Name: "i"},
+	{Name: "b"},
+	{Name: "u"},
+	{Name: "mark"},
+	{Name: "bdi"},
+	{Name: "bdo"},
+	{Name: "span"},
+	{Name: "br", Void: true},
+	{Name: "wbr", Void: true},
+	// edits
+	{Name: "ins"},
+	{Name: "del"},
+	// embedded content
+	{Name: "picture"},
+	{Name: "source", Void: true},
+	{Name: "img", Void: true},
+	{Name: "iframe"},
+	{Name: "embed", Void: true},
+	{Name: "object"},
+	{Name: "video"},
+	{Name: "audio"},
+	{Name: "track", Void: true},
+	{Name: "map"},
+	{Name: "area", Void: true},
+	// tabular data
+	{Name: "table"},
+	{Name: "caption"},
+	{Name: "colgroup"},
+	{Name: "col", Void: true},
+	{Name: "tbody"},
+	{Name: "thead"},
+	{Name: "tfoot"},
+	{Name: "tr"},
+	{Name: "td"},
+	{Name: "th"},
+	// forms
+	{Name: "form", Decorators: []string{"Submittable()"}},
+	{Name: "label"},
+	{Name: "input", Void: true, Decorators: []string{"Assignable(false)", "Clickable()", "Changeable()"}},
+	{Name: "button", Decorators: []string{"Assignable(false)", "Clickable()"}},
+	{Name: "select", Decorators: []string{"Selectable()", "Changeable()"}},
+	{Name: "datalist"},
+	{Name: "optgroup"},
+	{Name: "option"},
+	{Name: "textarea", Decorators: []string{"Assignable(true)", "Changeable()"}},
+	{Name: "output"},
+	{Name: "progress"},
+	{Name: "meter"},
+	{Name: "fieldset"},
+	{Name: "legend"},
+	// interactive elements
+	{Name: "details"},
+	{Name: "summary"},
+	{Name: "dialog"},
+	// scripting
+	{Name: "script", Decorators: []string{"isScript"}},
+	{Name: "noscript"},
+	{Name: "template"},
+	{Name: "slot"},
+	{Name: "canvas"},
+	// obsolete elements
+	{Name: "command", Void: true, Obsolete: true},
+	{Name: "keygen", Void: true, Obsolete: true},
+	{Name: "param", Void: true, Obsolete: true},
+	{Name: "portal", Obsolete: true},
+}
+
+var (
+	media    = []string{"audio", "video"}
+	formTags = []string{"button", "fieldset", "input", "object", "output", "select", "textarea"}
+	links    = []string{"a", "area"}
+)
+
+var attributes = []attribute{
+	// global attributes
+	{Name: "accesskey"},
+	{Name: "autocapitalize", Values: []string{"off", "none", "on", "sentences", "words", "characters"}},
+	{Name: "autofocus", Boolean: true},
+	{Name: "class"},
+	{Name: "contenteditable", Values: []string{"true", "false", "plaintext-only"}},
+	{Name: "dir", Values: []string{"ltr", "rtl", "auto"}},
+	{Name: "draggable", Values: []string{"true", "false"}},
+	{Name: "enterkeyhint", Values: []string{"enter", "done", "go", "next", "previous", "search", "send"}},
+	{Name: "hidden", Boolean: true},
+	{Name: "id"},
+	{Name: "inert", Boolean: true},
+	{Name: "inputmode", Values: []string{"none", "text", "tel", "email", "url", "numeric", "decimal", "search"}},
+	{Name: "is"},
+	{Name: "itemid"},
+	{Name: "itemprop"},
+	{Name: "itemref"},
+	{Name: "itemscope", Boolean: true},
+	{Name: "itemtype"},
+	{Name: "lang"},
+	{Name: "nonce"},
+	{Name: "popover", Values: []string{"auto", "manual"}},
+	{Name: "role"},
+	{Name: "slot"},
+	{Name: "spellcheck", Values: []string{"true", "false"}},
+	{Name: "style"},
+	{Name: "tabindex"},
+	{Name: "title"},
+	{Name: "translate", Values: []string{"yes", "no"}},
+
+	// element-specific attributes
+	{Name: "abbr", Elements: []string{"th"}},
+	{Name: "accept", Elements: []string{"input"}},
+	{Name: "accept-charset", Elements: []string{"form"}},
+	{Name: "action", Elements: []string{"form"}},
+	{Name: "allow", Elements: []string{"iframe"}},
+	{Name: "allowfullscreen", Boolean: true, Elements: []string{"iframe"}},
+	{Name: "alt", Elements: []string{"area", "img", "input"}},
+	{Name: "as", Elements: []string{"link"}},
+	{Name: "async", Boolean: true, Elements: []string{"script"}},
+	{Name: "autocomplete", Elements: []string{"form", "input", "select", "textarea"}},
+	{Name: "autoplay", Boolean: true, Elements: media},
+	{Name: "blocking", Elements: []string{"link", "script", "style"}},
+	{Name: "charset", Elements: []string{"meta"}},
+	{Name: "checked", Boolean: true, Elements: []string{"input"}},
+	{Name: "cite", Elements: []string{"blockquote", "del", "ins", "q"}},
+	{Name: "cols", Elements: []string{"textarea"}},
+	{Name: "colspan", Elements: []string{"td", "th"}},
+	{Name: "content", Elements: []string{"meta"}},
+	{Name: "controls", Boolean: true, Elements: media},
+	{Name: "coords", Elements: []string{"area"}},
+	{Name: "crossorigin", Values: []string{"anonymous", "use-credentials"}, Elements: []string{"audio", "img", "link", "script", "video"}},
+	{Name: "data", Elements: []string{"object"}},
+	{Name: "datetime", Elements: []string{"del", "ins", "time"}},
+	{Name: "decoding", Values: []string{"sync", "async", "auto"}, Elements: []string{"img"}},
+	{Name: "default", Boolean: true, Elements: []string{"track"}},
+	{Name: "defer", Boolean: true, Elements: []string{"script"}},
+	{Name: "dirname", Elements: []string{"input", "textarea"}},
+	{Name: "disabled", Boolean: true, Elements: []string{"button", "fieldset", "input", "link", "optgroup", "option", "select", "textarea"}},
+	// a boolean attribute for backwards compatibility
+	{Name: "download", Boolean: true, Elements: links},
+	{Name: "enctype", Values: []string{"application/x-www-form-urlencoded", "multipart/form-data", "text/plain"}, Elements: []string{"form"}},
+	{Name: "fetchpriority", Values: []string{"high", "low", "auto"}, Elements: []string{"img", "link", "script"}},
+	{Name: "for", Elements: []string{"label", "output"}},
+	{Name: "form", Elements: formTags},
+	{Name: "formaction", Elements: []string{"button", "input"}},
+	{Name: "formenctype", Values: []string{"application/x-www-form-urlencoded", "multipart/form-data", "text/plain"}, Elements: []string{"button", "input"}},
+	{Name: "formmethod", Values: []string{"get", "post", "dialog"}, Elements: []string{"button", "input"}},
+	{Name: "formnovalidate", Boolean: true, Elements: []string{"button", "input"}},
+	{Name: "formtarget", Elements: []string{"button", "input"}},
+	{Name: "headers", Elements: []string{"td", "th"}},
+	{Name: "height", Elements: []string{"canvas", "embed", "iframe", "img", "input", "object", "source", "video"}},
+	{Name: "high", Elements: []string{"meter"}},
+	{Name: "href", Elements: []string{"a", "area", "base", "link"}},
+	{Name: "hreflang", Elements: []string{"a", "link"}},
+	{Name: "http-equiv", Elements: []string{"meta"}},
+	{Name: "imagesizes", Elements: []string{"link"}},
+	{Name: "imagesrcset", Elements: []string{"link"}},
+	{Name: "integrity", Elements: []string{"link", "script"}},
+	{Name: "ismap", Boolean: true, Elements: []string{"img"}},
+	{Name: "kind", Values: []string{"subtitles", "captions", "descriptions", "chapters", "metadata"}, Elements: []string{"track"}},
+	{Name: "label", Elements: []string{"optgroup", "option", "track"}},
+	{Name: "list", Elements: []string{"input"}},
+	{Name: "loading", Values: []string{"lazy", "eager"}, Elements: []string{"iframe", "img"}},
+	{Name: "loop", Boolean: true, Elements: media},
+	{Name: "low", Elements: []string{"meter"}},
+	{Name: "max", Elements: []string{"input", "meter", "progress"}},
+	{Name: "maxlength", Elements: []string{"input", "textarea"}},
+	{Name: "media", Elements: []string{"link", "meta", "source", "style"}},
+	{Name: "method", Values: []string{"get", "post", "dialog"}, Elements: []string{"form"}},
+	{Name: "min", Elements: []string{"input", "meter"}},
+	{Name: "minlength", Elements: []string{"input", "textarea"}},
+	{Name: "multiple", Boolean: true, Elements: []string{"input", "select"}},
+	{Name: "muted", Boolean: true, Elements: media},
+	{Name: "name", Elements: []string{"button", "fieldset", "form", "iframe", "input", "map", "meta", "object", "output", "select", "slot", "textarea"}},
+	{Name: "nomodule", Boolean: true, Elements: []string{"script"}},
+	{Name: "novalidate", Boolean: true, Elements: []string{"form"}},
+	{Name: "open", Boolean: true, Elements: []string{"details", "dialog"}},
+	{Name: "optimum", Elements: []string{"meter"}},
+	{Name: "pattern", Elements: []string{"input"}},
+	{Name: "ping", Elements: links},
+	{Name: "placeholder", Elements: []string{"input", "textarea"}},
+	{Name: "playsinline", Boolean: true, Elements: []string{"video"}},
+	{Name: "popovertarget", Elements: []string{"button", "input"}},
+	{Name: "popovertargetaction", Values: []string{"toggle", "show", "hide"}, Elements: []string{"button", "input"}},
+	{Name: "poster", Elements: []string{"video"}},
+	{Name: "preload", Values: []string{"none", "metadata", "auto"}, Elements: media},
+	{Name: "readonly", Boolean: true, Elements: []string{"input", "textarea"}},
+	{Name: "referrerpolicy", Values: []string{"", "no-referrer", "no-referrer-when-downgrade", "same-origin", "origin", "strict-origin", "origin-when-cross-origin", "strict-origin-when-cross-origin", "unsafe-url"}, Elements: []string{"a", "area", "iframe", "img", "link", "script"}},
+	{Name: "rel", Elements: []string{"a", "area", "form", "link"}},
+	{Name: "required", Boolean: true, Elements: []string{"input", "select", "textarea"}},
+	{Name: "reversed", Boolean: true, Elements: []string{"ol"}},
+	{Name: "rows", Elements: []string{"textarea"}},
+	{Name: "rowspan", Elements: []string{"td", "th"}},
+	{Name: "sandbox", Elements: []string{"iframe"}},
+	{Name: "scope", Values: []string{"row", "col", "rowgroup", "colgroup"}, Elements: []string{"th"}},
+	{Name: "selected", Boolean: true, Elements: []string{"option"}},
+	{Name: "shadowrootmode", Values: []string{"open", "closed"}, Elements: []string{"template"}},
+	{Name: "shape", Values: []string{"circle", "default", "poly", "rect"}, Elements: []string{"area"}},
+	{Name: "size", Elements: []string{"input", "select"}},
+	{Name: "sizes", Elements: []string{"img", "link", "source"}},
+	{Name: "span", Elements: []string{"col", "colgroup"}},
+	{Name: "src", Elements: []string{"audio", "embed", "iframe", "img", "input", "script", "source", "track", "video"}},
+	{Name: "srcdoc", Elements: []string{"iframe"}},
+	{Name: "srclang", Elements: []string{"track"}},
+	{Name: "srcset", Elements: []string{"img", "source"}},
+	{Name: "start", Elements: []string{"ol"}},
+	{Name: "step", Elements: []string{"input"}},
+	{Name: "target", Elements: []string{"a", "area", "base", "form"}},
+	{Name: "type", Elements: []string{"a", "button", "embed", "input", "link", "object", "ol", "script", "source", "style"}},
+	{Name: "usemap", Elements: []string{"img"}},
+	{Name: "value", Elements: []string{"button", "data", "input", "li", "meter", "option", "output", "param", "progress"}},
+	{Name: "width", Elements: []string{"canvas", "embed", "iframe", "img", "input", "object", "source", "video"}},
+	{Name: "wrap", Values: []string{"soft", "hard"}, Elements: []string{"textarea"}},
+}
@@ -0,0 +1,535 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package gospel
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sync"
+)
+
+// A codec converts variable values to bytes and back
+type Codec interface {
+	// the name is stored alongside the data so we can decode it later on
+	Name() string
+	Encode(value any) ([]byte, error)
+	// decodes the data into the given pointer
+	Decode(data []byte, value any) error
+}
+
+type jsonCodec struct{}
+
+func (j *jsonCodec) Name() string {
+	return "json"
+}
+
+func (j *jsonCodec) Encode(value any) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (j *jsonCodec) Decode(data []byte, value any) error {
+	return json.Unmarshal(data, value)
+}
+
+type gobCodec struct{}
+
+func (g *gobCodec) Name() string {
+	return "gob"
+}
+
+func (g *gobCodec) Encode(value any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (g *gobCodec) Decode(data []byte, value any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(value)
+}
+
+// A compact, schema-less binary codec (similar to msgpack, but without type
+// markers as we always decode into a known type). Structs are encoded field
+// by field, so adding or reordering fields requires a new version.
+type binaryCodec struct{}
+
+func (b *binaryCodec) Name() string {
+	return "binary"
+}
+
+func (b *binaryCodec) Encode(value any) ([]byte, error) {
+	buf := make([]byte, 0, 64)
+	return appendBinary(buf, reflect.ValueOf(value))
+}
+
+func (b *binaryCodec) Decode(data []byte, value any) error {
+	v := reflect.ValueOf(value)
+
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return fmt.Errorf("expected a non-nil pointer, got %T", value)
+	}
+
+	if rest, err := readBinary(data, v.Elem()); err != nil {
+		return err
+	} else if len(rest) > 0 {
+		return fmt.Errorf("%d trailing bytes", len(rest))
+	}
+
+	return nil
+}
+
+var binaryMarshalerType = reflect.TypeOf((*encoding.BinaryMarshaler)(nil)).Elem()
+var binaryUnmarshalerType = reflect.TypeOf((*encoding.BinaryUnmarshaler)(nil)).Elem()
+
+// Checks whether values of the type encode themselves. We check the pointer
+// type on both sides, as methods might have pointer receivers.
+func isBinaryMarshaler(t reflect.Type) bool {
+	pt := reflect.PointerTo(t)
+	return t.Kind() != reflect.Pointer && pt.Implements(binaryMarshalerType) && pt.Implements(binaryUnmarshalerType)
+}
+
+// Returns the minimum number of bytes that an encoded value of the type
+// takes, so that we can check lengths before allocating memory for them
+func minBinarySize(t reflect.Type) int {
+
+	if isBinaryMarshaler(t) {
+		return 1
+	}
+
+	switch t.Kind() {
+	case reflect.Float32:
+		return 4
+	case reflect.Float64:
+		return 8
+	case reflect.Array:
+		return t.Len() * minBinarySize(t.Elem())
+	case reflect.Struct:
+		size := 0
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).IsExported() {
+				size += minBinarySize(t.Field(i).Type)
+			}
+		}
+		return size
+	}
+
+	return 1
+}
+
+func appendBytes(buf []byte, data []byte) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(data)))
+	return append(buf, data...)
+}
+
+func appendBinary(buf []byte, v reflect.Value) ([]byte, error) {
+
+	if !v.IsValid() {
+		return nil, fmt.Errorf("cannot encode an invalid value")
+	}
+
+	// types like time.Time know how to encode themselves
+	if isBinaryMarshaler(v.Type()) {
+		if !v.CanAddr() {
+			// the method might have a pointer receiver
+			pv := reflect.New(v.Type())
+			pv.Elem().Set(v)
+			v = pv.Elem()
+		}
+		if data, err := v.Addr().Interface().(encoding.BinaryMarshaler).MarshalBinary(); err != nil {
+			return nil, err
+		} else {
+			return appendBytes(buf, data), nil
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return append(buf, 1), nil
+		}
+		return append(buf, 0), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return binary.AppendVarint(buf, v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return binary.AppendUvarint(buf, v.Uint()), nil
+	case reflect.Float32:
+		return binary.LittleEndian.AppendUint32(buf, math.Float32bits(float32(v.Float()))), nil
+	case reflect.Float64:
+		return binary.LittleEndian.AppendUint64(buf, math.Float64bits(v.Float())), nil
+	case reflect.String:
+		return appendBytes(buf, []byte(v.String())), nil
+	case reflect.Pointer:
+		if v.IsNil() {
+			return append(buf, 0), nil
+		}
+		return appendBinary(append(buf, 1), v.Elem())
+	case reflect.Slice:
+		// we store the length + 1 so that we can distinguish nil from empty slices
+		if v.IsNil() {
+			return append(buf, 0), nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			buf = binary.AppendUvarint(buf, uint64(v.Len()+1))
+			return append(buf, v.Bytes()...), nil
+		}
+		fallthrough
+	case reflect.Array:
+		var err error
+		buf = binary.AppendUvarint(buf, uint64(v.Len()+1))
+		for i := 0; i < v.Len(); i++ {
+			if buf, err = appendBinary(buf, v.Index(i)); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	case reflect.Map:
+		if v.IsNil() {
+			return append(buf, 0), nil
+		}
+		var err error
+		buf = binary.AppendUvarint(buf, uint64(v.Len()+1))
+		iter := v.MapRange()
+		for iter.Next() {
+			if buf, err = appendBinary(buf, iter.Key()); err != nil {
+				return nil, err
+			}
+			if buf, err = appendBinary(buf, iter.Value()); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	case reflect.Struct:
+		var err error
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Type().Field(i).IsExported() {
+				continue
+			}
+			if buf, err = appendBinary(buf, v.Field(i)); err != nil {
+				return nil, fmt.Errorf("field %s: %w", v.Type().Field(i).Name, err)
+			}
+		}
+		return buf, nil
+	}
+
+	return nil, fmt.Errorf("cannot encode values of type %s", v.Type())
+}
+
+func readUvarint(data []byte) (uint64, []byte, error) {
+	value, n := binary.Uvarint(data)
+	if n <= 0 {
+		return 0, nil, fmt.Errorf("invalid unsigned integer")
+	}
+	return value, data[n:], nil
+}
+
+func readBytes(data []byte) ([]byte, []byte, error) {
+	l, data, err := readUvarint(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if uint64(len(data)) < l {
+		return nil, nil, fmt.Errorf("unexpected end of data")
+	}
+	return data[:l], data[l:], nil
+}
+
+func readBinary(data []byte, v reflect.Value) ([]byte, error) {
+
+	if isBinaryMarshaler(v.Type()) {
+		if value, rest, err := readBytes(data); err != nil {
+			return nil, err
+		} else {
+			return rest, v.Addr().Interface().(encoding.BinaryUnmarshaler).UnmarshalBinary(value)
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if len(data) == 0 {
+			return nil, fmt.Errorf("unexpected end of data")
+		}
+		v.SetBool(data[0] == 1)
+		return data[1:], nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, n := binary.Varint(data)
+		if n <= 0 {
+			return nil, fmt.Errorf("invalid integer")
+		}
+		v.SetInt(value)
+		return data[n:], nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		value, rest, err := readUvarint(data)
+		if err != nil {
+			return nil, err
+		}
+		v.SetUint(value)
+		return rest, nil
+	case reflect.Float32:
+		if len(data) < 4 {
+			return nil, fmt.Errorf("unexpected end of data")
+		}
+		v.SetFloat(float64(math.Float32frombits(binary.LittleEndian.Uint32(data))))
+		return data[4:], nil
+	case reflect.Float64:
+		if len(data) < 8 {
+			return nil, fmt.Errorf("unexpected end of data")
+		}
+		v.SetFloat(math.Float64frombits(binary.LittleEndian.Uint64(data)))
+		return data[8:], nil
+	case reflect.String:
+		value, rest, err := readBytes(data)
+		if err != nil {
+			return nil, err
+		}
+		v.SetString(string(value))
+		return rest, nil
+	case reflect.Pointer:
+		if len(data) == 0 {
+			return nil, fmt.Errorf("unexpected end of data")
+		}
+		if data[0] == 0 {
+			v.Set(reflect.Zero(v.Type()))
+			return data[1:], nil
+		}
+		nv := reflect.New(v.Type().Elem())
+		rest, err := readBinary(data[1:], nv.Elem())
+		if err != nil {
+			return nil, err
+		}
+		v.Set(nv)
+		return rest, nil
+	case reflect.Slice, reflect.Array, reflect.Map:
+		l, rest, err := readUvarint(data)
+		if err != nil {
+			return nil, err
+		}
+		if l == 0 {
+			v.Set(reflect.Zero(v.Type()))
+			return rest, nil
+		}
+		// the length comes from the client, so we make sure that the data
+		// can actually contain that many elements before allocating them
+		size := 1
+		switch v.Kind() {
+		case reflect.Slice, reflect.Array:
+			size = minBinarySize(v.Type().Elem())
+		case reflect.Map:
+			size = minBinarySize(v.Type().Key()) + minBinarySize(v.Type().Elem())
+		}
+		if size < 1 {
+			size = 1
+		}
+		if l-1 > uint64(len(rest)/size) {
+			return nil, fmt.Errorf("invalid length %d", l-1)
+		}
+		n := int(l - 1)
+		switch v.Kind() {
+		case reflect.Slice:
+			if v.Type().Elem().Kind() == reflect.Uint8 {
+				if len(rest) < n {
+					return nil, fmt.Errorf("unexpected end of data")
+				}
+				v.SetBytes(append([]byte{}, rest[:n]...))
+				return rest[n:], nil
+			}
+			v.Set(reflect.MakeSlice(v.Type(), n, n))
+		case reflect.Array:
+			if n != v.Len() {
+				return nil, fmt.Errorf("expected an array of length %d, got %d", v.Len(), n)
+			}
+		case reflect.Map:
+			m := reflect.MakeMapWithSize(v.Type(), n)
+			for i := 0; i < n; i++ {
+				key := reflect.New(v.Type().Key()).Elem()
+				value := reflect.New(v.Type().Elem()).Elem()
+				if rest, err = readBinary(rest, key); err != nil {
+					return nil, err
+				}
+				if rest, err = readBinary(rest, value); err != nil {
+					return nil, err
+				}
+				m.SetMapIndex(key, value)
+			}
+			v.Set(m)
+			return rest, nil
+		}
+		for i := 0; i < n; i++ {
+			if rest, err = readBinary(rest, v.Index(i)); err != nil {
+				return nil, err
+			}
+		}
+		return rest, nil
+	case reflect.Struct:
+		var err error
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Type().Field(i).IsExported() {
+				continue
+			}
+			if data, err = readBinary(data, v.Field(i)); err != nil {
+				return nil, fmt.Errorf("field %s: %w", v.Type().Field(i).Name, err)
+			}
+		}
+		return data, nil
+	}
+
+	return nil, fmt.Errorf("cannot decode values of type %s", v.Type())
+}
+
+type funcCodec[T any] struct {
+	name   string
+	encode func(T) ([]byte, error)
+	decode func([]byte) (T, error)
+}
+
+func (f *funcCodec[T]) Name() string {
+	return f.name
+}
+
+func (f *funcCodec[T]) Encode(value any) ([]byte, error) {
+	if vt, ok := value.(T); !ok {
+		return nil, fmt.Errorf("type error: %T vs. %T", value, *new(T))
+	} else {
+		return f.encode(vt)
+	}
+}
+
+func (f *funcCodec[T]) Decode(data []byte, value any) error {
+	ptr, ok := value.(*T)
+
+	if !ok {
+		return fmt.Errorf("type error: %T vs. %T", value, new(T))
+	}
+
+	if vt, err := f.decode(data); err != nil {
+		return err
+	} else {
+		*ptr = vt
+	}
+
+	return nil
+}
+
+var JSONCodec Codec = &jsonCodec{}
+var GobCodec Codec = &gobCodec{}
+var BinaryCodec Codec = &binaryCodec{}
+
+// Creates a custom codec for a given type from an encoding and decoding function
+func MakeCodec[T any](name string, encode func(T) ([]byte, error), decode func([]byte) (T, error)) Codec {
+	return &funcCodec[T]{
+		name:   name,
+		encode: encode,
+		decode: decode,
+	}
+}
+
+// A migration function converts data that was stored with an older
+// version of a type into the current version
+type MigrationFunc[T any] func(version int, data []byte, codec Codec) (T, error)
+
+type typeCodec struct {
+	codec   Codec
+	version int
+	migrate any
+}
+
+var codecsMutex sync.RWMutex
+var codecsByType = map[reflect.Type]*typeCodec{}
+
+func typeCodecFor[T any]() *typeCodec {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+
+	if tc, ok := codecsByType[t]; ok {
+		return tc
+	}
+
+	tc := &typeCodec{codec: JSONCodec}
+	codecsByType[t] = tc
+	return tc
+}
+
+// Registers the codec that is used to serialize variables of type T
+func RegisterCodec[T any](codec Codec) {
+	codecsMutex.Lock()
+	defer codecsMutex.Unlock()
+
+	typeCodecFor[T]().codec = codec
+}
+
+// Sets the current version of type T. Stored values with a different
+// version will be passed to the migration function upon deserialization.
+func RegisterVersion[T any](version int, migrate MigrationFunc[T]) {
+	codecsMutex.Lock()
+	defer codecsMutex.Unlock()
+
+	tc := typeCodecFor[T]()
+	tc.version = version
+	tc.migrate = migrate
+}
+
+func lookupTypeCodec[T any]() (Codec, int, MigrationFunc[T]) {
+	codecsMutex.RLock()
+	defer codecsMutex.RUnlock()
+
+	tc, ok := codecsByType[reflect.TypeOf((*T)(nil)).Elem()]
+
+	if !ok {
+		return JSONCodec, 0, nil
+	}
+
+	migrate, _ := tc.migrate.(MigrationFunc[T])
+
+	return tc.codec, tc.version, migrate
+}
+
+// serialized values start with a byte that can't appear in JSON, so that
+// we can still read values that were stored as plain JSON
+const envelopeMagic = 0xff
+
+func encodeEnvelope(codec string, version int, data []byte) []byte {
+	buf := make([]byte, 0, len(data)+len(codec)+4)
+	buf = append(buf, envelopeMagic)
+	buf = binary.AppendUvarint(buf, uint64(version))
+	buf = appendBytes(buf, []byte(codec))
+	return append(buf, data...)
+}
+
+func decodeEnvelope(data []byte) (string, int, []byte, error) {
+
+	if len(data) == 0 || data[0] != envelopeMagic {
+		// this is a legacy JSON value
+		return JSONCodec.Name(), 0, data, nil
+	}
+
+	version, rest, err := readUvarint(data[1:])
+
+	if err != nil {
+		return "", 0, nil, fmt.Errorf("invalid version: %w", err)
+	}
+
+	codec, rest, err := readBytes(rest)
+
+	if err != nil {
+		return "", 0, nil, fmt.Errorf("invalid codec: %w", err)
+	}
+
+	return string(codec), int(version), rest, nil
+}
@@ -0,0 +1,221 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package gospel
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"github.com/google/go-cmp/cmp"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type codecTestValue struct {
+	Name     string
+	Count    int
+	Ratio    float64
+	Created  time.Time
+	Blob     []byte
+	Tags     []string
+	Counts   map[string]int
+	Optional *bool
+}
+
+func TestCodecs(t *testing.T) {
+
+	enabled := true
+
+	value := codecTestValue{
+		Name:     "test",
+		Count:    -42,
+		Ratio:    0.5,
+		Created:  time.Date(2024, 1, 2, 3, 4, 5, 6789, time.UTC),
+		Blob:     []byte{0, 1, 2, 255},
+		Tags:     []string{"a", "b"},
+		Counts:   map[string]int{"x": 1},
+		Optional: &enabled,
+	}
+
+	for _, codec := range []Codec{JSONCodec, GobCodec, BinaryCodec} {
+
+		v := MakeVarObj[codecTestValue](nil, nil)
+		v.SetCodec(codec)
+		v.Set(value)
+
+		data, err := v.Serialize()
+
+		if err != nil {
+			t.Fatalf("%s: cannot serialize: %v", codec.Name(), err)
+		}
+
+		nv := MakeVarObj[codecTestValue](nil, nil)
+		nv.SetCodec(codec)
+
+		if err := nv.Deserialize(data); err != nil {
+			t.Fatalf("%s: cannot deserialize: %v", codec.Name(), err)
+		}
+
+		if diff := cmp.Diff(value, nv.Get()); diff != "" {
+			t.Fatalf("%s: invalid result: %s", codec.Name(), diff)
+		}
+	}
+}
+
+// a type whose methods have pointer receivers
+type pointerMarshaler struct {
+	Value string
+}
+
+func (p *pointerMarshaler) MarshalBinary() ([]byte, error) {
+	return []byte("custom:" + p.Value), nil
+}
+
+func (p *pointerMarshaler) UnmarshalBinary(data []byte) error {
+	value, ok := strings.CutPrefix(string(data), "custom:")
+	if !ok {
+		return fmt.Errorf("invalid data")
+	}
+	p.Value = value
+	return nil
+}
+
+func TestBinaryMarshalers(t *testing.T) {
+
+	value := map[string]pointerMarshaler{"a": {"x"}}
+
+	data, err := BinaryCodec.Encode(value)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Contains(data, []byte("custom:x")) {
+		t.Fatalf("expected the custom encoding")
+	}
+
+	var decoded map[string]pointerMarshaler
+
+	if err := BinaryCodec.Decode(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(value, decoded); diff != "" {
+		t.Fatalf("invalid result: %s", diff)
+	}
+}
+
+func TestForgedData(t *testing.T) {
+
+	// a huge length that the data can't contain
+	forged := binary.AppendUvarint(nil, math.MaxUint64)
+
+	for _, value := range []any{new([]int), new([]byte), new(map[string]int), new([]struct{})} {
+		if err := BinaryCodec.Decode(forged, value); err == nil {
+			t.Fatalf("%T: expected an error", value)
+		}
+	}
+
+	// clients can't choose the codec of a variable
+	v := MakeVarObj[[]int](nil, nil)
+
+	if err := v.Deserialize(encodeEnvelope(BinaryCodec.Name(), 0, forged)); err == nil {
+		t.Fatalf("expected an error for a different codec")
+	}
+
+	v.SetCodec(BinaryCodec)
+
+	if err := v.Deserialize(encodeEnvelope(BinaryCodec.Name(), 0, forged)); err == nil {
+		t.Fatalf("expected an error for an invalid length")
+	}
+
+	// JSON is always accepted for older values
+	if err := v.Deserialize(encodeEnvelope(JSONCodec.Name(), 0, []byte("[1]"))); err != nil || len(v.Get()) != 1 {
+		t.Fatalf("expected a JSON value: %v", err)
+	}
+}
+
+func TestLegacyJSON(t *testing.T) {
+
+	v := MakeVarObj[[]int](nil, nil)
+
+	if err := v.Deserialize([]byte("[1,2,3]")); err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff([]int{1, 2, 3}, v.Get()); diff != "" {
+		t.Fatalf("invalid result: %s", diff)
+	}
+}
+
+type versionedTestValue struct {
+	Count int
+}
+
+func TestVersionMigration(t *testing.T) {
+
+	// version 0 stored the count as a string
+	old := MakeVarObj[string](nil, nil)
+	old.Set("12")
+	data, err := old.Serialize()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	RegisterCodec[versionedTestValue](MakeCodec("count", func(v versionedTestValue) ([]byte, error) {
+		return []byte(fmt.Sprint(v.Count)), nil
+	}, func(data []byte) (versionedTestValue, error) {
+		count, err := strconv.Atoi(string(data))
+		return versionedTestValue{count}, err
+	}))
+
+	RegisterVersion[versionedTestValue](1, func(version int, data []byte, codec Codec) (versionedTestValue, error) {
+		var count string
+		if err := codec.Decode(data, &count); err != nil {
+			return versionedTestValue{}, err
+		}
+		n, err := strconv.Atoi(count)
+		return versionedTestValue{n}, err
+	})
+
+	v := MakeVarObj[versionedTestValue](nil, nil)
+
+	if err := v.Deserialize(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.Get().Count != 12 {
+		t.Fatalf("expected 12, got %d", v.Get().Count)
+	}
+
+	// values of the current version use the registered codec
+	if data, err = v.Serialize(); err != nil {
+		t.Fatal(err)
+	}
+
+	nv := MakeVarObj[versionedTestValue](nil, nil)
+
+	if err := nv.Deserialize(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if nv.Get().Count != 12 {
+		t.Fatalf("expected 12, got %d", nv.Get().Count)
+	}
+}
@@ -0,0 +1,150 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package gospel
+
+import (
+	"fmt"
+	"reflect"
+)
+
+const (
+	// children that aren't assigned to a named slot
+	DefaultSlot = "default"
+	HeaderSlot  = "header"
+	BodySlot    = "body"
+	FooterSlot  = "footer"
+)
+
+// Children that should be placed in a named slot of a component
+type SlotContent struct {
+	Name     string
+	Children []any
+}
+
+func InSlot(name string, children ...any) *SlotContent {
+	return &SlotContent{
+		Name:     name,
+		Children: children,
+	}
+}
+
+// Named child regions of a component
+type Slots map[string][]any
+
+func MakeSlots(children ...any) Slots {
+
+	slots := Slots{}
+
+	for _, child := range children {
+		if content, ok := child.(*SlotContent); ok {
+			slots[content.Name] = append(slots[content.Name], content.Children...)
+		} else {
+			slots[DefaultSlot] = append(slots[DefaultSlot], child)
+		}
+	}
+
+	return slots
+}
+
+func (s Slots) Has(name string) bool {
+	return len(s[name]) > 0
+}
+
+// Returns the content of the given slot, or nil if it is empty
+func (s Slots) Get(name string) Element {
+
+	if !s.Has(name) {
+		return nil
+	}
+
+	return F(s[name]...)
+}
+
+type ComponentFunction[P any] func(c Context, props P, slots Slots) Element
+
+// A reusable element with typed props and named slots. Props that are
+// not set (i.e. have a zero value) are taken from the defaults, so fields
+// whose zero value is meaningful should be pointers.
+type Component[P any] struct {
+	Name     string
+	Defaults P
+	render   ComponentFunction[P]
+}
+
+func MakeComponent[P any](name string, defaults P, render ComponentFunction[P]) *Component[P] {
+
+	if name == "" {
+		panic("empty component name")
+	}
+
+	return &Component[P]{
+		Name:     name,
+		Defaults: defaults,
+		render:   render,
+	}
+}
+
+// Renders the component as a child element of the given context. The key of
+// the element is derived from the name of the component and the number of
+// times it was used in the context, so its variables are kept apart.
+func (cp *Component[P]) Render(c Context, props P, children ...any) Element {
+
+	i := c.NextIndex(c.Key() + ".component." + cp.Name)
+	key := fmt.Sprintf("%s.%d", cp.Name, i)
+
+	props = MergeProps(cp.Defaults, props)
+	slots := MakeSlots(children...)
+
+	return c.Element(key, func(c Context) Element {
+		return cp.render(c, props, slots)
+	})
+}
+
+// Like Render, but returns an element function e.g. for use in routes
+func (cp *Component[P]) Func(props P, children ...any) ElementFunction {
+	return func(c Context) Element {
+		return cp.Render(c, props, children...)
+	}
+}
+
+// Returns the props with all unset fields taken from the defaults
+func MergeProps[P any](defaults, props P) P {
+
+	value := reflect.ValueOf(&props).Elem()
+	defaultValue := reflect.ValueOf(defaults)
+
+	if value.Kind() != reflect.Struct {
+		if value.IsZero() {
+			return defaults
+		}
+		return props
+	}
+
+	for i := 0; i < value.NumField(); i++ {
+
+		field := value.Field(i)
+
+		// we can't set unexported fields
+		if !field.CanSet() {
+			continue
+		}
+
+		if field.IsZero() {
+			field.Set(defaultValue.Field(i))
+		}
+	}
+
+	return props
+}
@@ -0,0 +1,73 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package gospel
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"testing"
+)
+
+type cardProps struct {
+	Title    string
+	Level    int
+	Bordered *bool
+}
+
+func TestComponents(t *testing.T) {
+
+	bordered := true
+	keys := []string{}
+
+	card := MakeComponent("card", cardProps{Title: "Untitled", Level: 2, Bordered: &bordered}, func(c Context, props cardProps, slots Slots) Element {
+
+		keys = append(keys, c.Key())
+
+		class := "card"
+
+		if *props.Bordered {
+			class += " bordered"
+		}
+
+		return Div(
+			Class(class),
+			H2(props.Title),
+			If(slots.Has(HeaderSlot), Header(slots.Get(HeaderSlot))),
+			slots.Get(DefaultSlot),
+			Footer(slots.Get(FooterSlot)),
+		)
+	})
+
+	notBordered := false
+
+	c := makeTestContext()
+
+	html := c.Execute(func(c Context) Element {
+		return F(
+			card.Render(c, cardProps{Title: "First"}, InSlot(FooterSlot, "end"), "body"),
+			card.Render(c, cardProps{Bordered: &notBordered}, InSlot(HeaderSlot, "head")),
+		)
+	}).RenderElement()
+
+	expected := `<div class="card bordered"><h2>First</h2>body<footer>end</footer></div>` +
+		`<div class="card"><h2>Untitled</h2><header>head</header><footer></footer></div>`
+
+	if diff := cmp.Diff(expected, html); diff != "" {
+		t.Fatalf("unexpected output: %s", diff)
+	}
+
+	if diff := cmp.Diff([]string{"root.card.0", "root.card.1"}, keys); diff != "" {
+		t.Fatalf("unexpected keys: %s", diff)
+	}
+}
@@ -15,8 +15,12 @@
 package gospel
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"reflect"
+	"strings"
+	"sync"
 )
 
 type ElementFunction func(c Context) Element
@@ -24,12 +28,16 @@ type PureElementFunction = func() Element
 type RespondWithFunction func(c Context, w http.ResponseWriter)
 
 type Context interface {
+	App() *App
+	Ctx() context.Context
 	Request() *http.Request
 	Execute(ElementFunction) Element
 	SetRespondWith(RespondWithFunction)
 	RespondWith() RespondWithFunction
 	ElementFunction(string, ElementFunction) ElementFunction
 	DeferElement(string, ElementFunction) PureElementFunction
+	AsyncElement(string, ElementFunction) PureElementFunction
+	Suspend(id string, await PureElementFunction)
 	Element(string, ElementFunction) Element
 	GetVar(key string) ContextVarObj
 	SetById(id string, value any) error
@@ -42,6 +50,14 @@ type Context interface {
 	ResponseWriter() http.ResponseWriter
 	Scope(string) Context
 	Key() string
+	Track(id string)
+	Invalidate(id string)
+	Notify(variable ContextVarObj)
+	Batch(func())
+	MemoCache() *MemoCache
+	NextIndex(key string) int
+	Service(t reflect.Type) (any, error)
+	OnFinalize(func())
 	Clear()
 }
 
@@ -52,8 +68,12 @@ type DefaultContext struct {
 	respondWith RespondWithFunction
 	request     *http.Request
 	writer      http.ResponseWriter
+	app         *App
 	root        *DefaultContext
-	Store       *Store
+	async       bool
+	// the context of asynchronous elements, see AsyncElement
+	ctx   context.Context
+	Store *Store
 }
 
 type PersistentStore interface {
@@ -62,10 +82,31 @@ type PersistentStore interface {
 	Clear()
 }
 
+// The store is shared by all contexts of a request. As asynchronous elements
+// access it from different goroutines, all methods are safe for concurrent use.
 type Store struct {
+	mutex           sync.Mutex
 	VariableIndices map[string]int
 	Variables       map[string]ContextVarObj
 	Funcs           map[string][]ContextFuncObj[any]
+	// maps element keys to the variables they read (and the revision at which they read them)
+	Dependencies map[string]map[string]int
+	elementStack []string
+	// keys of the elements executed during the current render
+	executed map[string]bool
+	// keys of asynchronous elements, whose dependencies we don't track
+	volatile map[string]bool
+	changes  map[string]int
+	revision int
+	// memoized values, usually shared between requests
+	Memo            *MemoCache
+	batchDepth      int
+	pending         []ContextVarObj
+	services        map[reflect.Type]any
+	suspended       int
+	resolved        chan *suspendedElement
+	cleanups        []func()
+	finalizers      []func()
 	persistentStore PersistentStore
 }
 
@@ -88,28 +129,197 @@ func MakeStore(persistentStore PersistentStore) *Store {
 		Variables:       make(map[string]ContextVarObj),
 		VariableIndices: make(map[string]int),
 		Funcs:           make(map[string][]ContextFuncObj[any]),
+		Dependencies:    make(map[string]map[string]int),
+		executed:        make(map[string]bool),
+		volatile:        make(map[string]bool),
+		changes:         make(map[string]int),
+		Memo:            MakeMemoCache(DefaultMemoCacheSize),
 		persistentStore: persistentStore,
 	}
 }
 
 func (s *Store) SetById(id string, value any) error {
-	if variable, ok := s.Variables[id]; ok {
+	// we don't hold the lock while setting the value, as the variable
+	// calls back into the store to record the change
+	if variable := s.GetById(id); variable != nil {
 		return variable.Set(value)
 	}
 	return fmt.Errorf("not found")
 }
 
 func (s *Store) GetById(id string) ContextVarObj {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 	v, _ := s.Variables[id]
 	return v
 }
 
 func (s *Store) Flush() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 	s.Funcs = make(map[string][]ContextFuncObj[any])
 	s.VariableIndices = make(map[string]int)
 }
 
+// Records that the currently executing element read the given variable
+func (s *Store) Track(id string) {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if id == "" || len(s.elementStack) == 0 {
+		return
+	}
+
+	key := s.elementStack[len(s.elementStack)-1]
+	deps, ok := s.Dependencies[key]
+
+	if !ok {
+		deps = make(map[string]int)
+		s.Dependencies[key] = deps
+	}
+
+	deps[id] = s.revision
+}
+
+// Records that the given variable has changed
+func (s *Store) Invalidate(id string) {
+
+	if id == "" {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.revision++
+	s.changes[id] = s.revision
+}
+
+// Checks whether a variable read by the element (or one of its children)
+// has changed since the element was executed, e.g. to decide whether a part
+// of the page has to be rendered again
+func (s *Store) Dirty(key string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for volatileKey := range s.volatile {
+		// elements containing asynchronous children are always dirty
+		if volatileKey == key || strings.HasPrefix(volatileKey, key+".") {
+			return true
+		}
+	}
+
+	for elementKey, deps := range s.Dependencies {
+		if elementKey != key && !strings.HasPrefix(elementKey, key+".") {
+			continue
+		}
+		for id, revision := range deps {
+			if s.changes[id] > revision {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Notifies the subscribers of a variable, or queues the notification
+// if we're in a batch
+func (s *Store) Notify(variable ContextVarObj) {
+
+	s.mutex.Lock()
+
+	if s.batchDepth == 0 {
+		s.mutex.Unlock()
+		variable.Notify()
+		return
+	}
+
+	defer s.mutex.Unlock()
+
+	for _, pendingVariable := range s.pending {
+		if pendingVariable == variable {
+			return
+		}
+	}
+
+	s.pending = append(s.pending, variable)
+}
+
+func (s *Store) Batch(f func()) {
+
+	s.mutex.Lock()
+	s.batchDepth++
+	s.mutex.Unlock()
+
+	defer func() {
+		s.mutex.Lock()
+		s.batchDepth--
+
+		if s.batchDepth > 0 {
+			s.mutex.Unlock()
+			return
+		}
+
+		pending := s.pending
+		s.pending = nil
+		s.mutex.Unlock()
+
+		for _, variable := range pending {
+			variable.Notify()
+		}
+	}()
+
+	f()
+}
+
+// Forgets the variables the element read during the last render, as it's
+// being executed again. Elements with the same key share their dependencies,
+// so we only do this for the first one.
+func (s *Store) resetDependencies(key string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.executed[key] {
+		return
+	}
+	s.executed[key] = true
+	delete(s.Dependencies, key)
+}
+
+// Starts a new render, in which every element is executed again
+func (s *Store) resetExecuted() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.executed = make(map[string]bool)
+}
+
+func (s *Store) PushElement(key string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.elementStack = append(s.elementStack, key)
+}
+
+func (s *Store) PopElement() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if len(s.elementStack) > 0 {
+		s.elementStack = s.elementStack[:len(s.elementStack)-1]
+	}
+}
+
+// Returns the number of times the index for the given key was requested
+// during the current render
+func (s *Store) NextIndex(key string) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	i := s.VariableIndices[key]
+	s.VariableIndices[key] = i + 1
+	return i
+}
+
 func (s *Store) GetVar(key string) ContextVarObj {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 	if variable, ok := s.Variables[key]; ok {
 		return variable
 	}
@@ -121,11 +331,77 @@ func (s *Store) Clear() {
 }
 
 func (s *Store) AddFunc(key string, callback ContextFuncObj[any]) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 	s.Funcs[key] = append(s.Funcs[key], callback)
 	return len(s.Funcs[key])
 }
 
+func (s *Store) service(t reflect.Type) (any, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	instance, ok := s.services[t]
+	return instance, ok
+}
+
+func (s *Store) setService(t reflect.Type, instance any) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.services == nil {
+		s.services = make(map[reflect.Type]any)
+	}
+	s.services[t] = instance
+}
+
+// Registers a function that is called when the request is done
+func (s *Store) OnCleanup(cleanup func()) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.cleanups = append(s.cleanups, cleanup)
+}
+
+// Calls all cleanup functions in reverse order
+func (s *Store) Cleanup() {
+	s.mutex.Lock()
+	cleanups := s.cleanups
+	s.cleanups = nil
+	s.services = nil
+	s.mutex.Unlock()
+
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		cleanups[i]()
+	}
+}
+
+// Marks an element as asynchronous
+func (s *Store) setVolatile(key string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.volatile[key] = true
+}
+
+// Registers a function that is called before persistent variables are stored
+func (s *Store) OnFinalize(finalizer func()) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.finalizers = append(s.finalizers, finalizer)
+}
+
 func (s *Store) Finalize() {
+
+	s.mutex.Lock()
+	finalizers := s.finalizers
+	s.finalizers = nil
+	s.mutex.Unlock()
+
+	// finalizers can still change variables
+	for _, finalizer := range finalizers {
+		finalizer()
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	for key, variable := range s.Variables {
 		if variable.Persistent() {
 			s.persistentStore.Set(key, variable)
@@ -138,6 +414,8 @@ func (s *Store) AddVar(variable ContextVarObj, key string, global bool) error {
 	var i int
 	var fullKey string
 
+	s.mutex.Lock()
+
 	// for global variables, the index will always be 0
 	if !global {
 		// by default, we'll use the 0 index
@@ -151,6 +429,7 @@ func (s *Store) AddVar(variable ContextVarObj, key string, global bool) error {
 	variable.SetId(fullKey)
 
 	if _, ok := s.Variables[fullKey]; ok {
+		s.mutex.Unlock()
 		variable.SetCopy(true)
 		return nil
 	}
@@ -160,6 +439,8 @@ func (s *Store) AddVar(variable ContextVarObj, key string, global bool) error {
 
 	s.Variables[fullKey] = variable
 
+	s.mutex.Unlock()
+
 	// we check if the variable exists in the persistent store
 
 	if variable.Persistent() {
@@ -194,11 +475,41 @@ func (d *DefaultContext) SetStatusCode(code int) {
 	d.root.statusCode = code
 }
 
+func (d *DefaultContext) App() *App {
+	return d.root.app
+}
+
+func (d *DefaultContext) SetApp(app *App) {
+	d.root.app = app
+}
+
+// Returns the Go context of the request, which is canceled when the client
+// disconnects or the render timeout of the app is exceeded
+func (d *DefaultContext) Ctx() context.Context {
+	if d.ctx != nil {
+		return d.ctx
+	}
+	if d.root.request == nil {
+		return context.Background()
+	}
+	return d.root.request.Context()
+}
+
 func (d *DefaultContext) Request() *http.Request {
 	return d.root.request
 }
 
+// Checks whether the context belongs to an asynchronous element that is
+// still running after the deadline of the request. As the store might
+// already be finalized, such elements can't change it anymore.
+func (d *DefaultContext) detached() bool {
+	return d.ctx != nil && d.ctx.Err() != nil
+}
+
 func (d *DefaultContext) SetById(id string, variable any) error {
+	if d.detached() {
+		return fmt.Errorf("cannot set variable '%s': %w", id, d.ctx.Err())
+	}
 	return d.root.Store.SetById(id, variable)
 }
 
@@ -221,35 +532,130 @@ func (d *DefaultContext) ElementFunction(key string, elementFunction ElementFunc
 func (d *DefaultContext) DeferElement(key string, elementFunction ElementFunction) PureElementFunction {
 
 	c := &DefaultContext{
-		key:  fmt.Sprintf("%s.%s", d.key, key),
-		root: d.root,
+		key:   fmt.Sprintf("%s.%s", d.key, key),
+		root:  d.root,
+		async: d.async,
+		ctx:   d.ctx,
 	}
 
 	return func() Element {
+		if !c.async {
+			c.root.Store.PushElement(c.key)
+			defer c.root.Store.PopElement()
+		}
 		return elementFunction(c)
 	}
 }
 
+// Starts executing the element function in a separate goroutine and returns
+// a function that waits for the result. The element function shares the
+// deadline of the request, if it isn't done by then we render nothing. Its
+// context is canceled at the deadline (or when the request is done, as
+// suspended elements are still streamed after the store is finalized), and
+// from then on, its own variables are no longer added to the store.
+func (d *DefaultContext) AsyncElement(key string, elementFunction ElementFunction) PureElementFunction {
+
+	ctx, cancel := context.WithCancel(d.Ctx())
+
+	c := &DefaultContext{
+		key:   fmt.Sprintf("%s.%s", d.key, key),
+		root:  d.root,
+		async: true,
+		ctx:   ctx,
+	}
+
+	// elements that are never awaited mustn't use the store after the request
+	d.root.Store.OnCleanup(cancel)
+
+	// elements with asynchronous children are always dirty, as we can't
+	// reliably track which variables they depend on
+	d.root.Store.setVolatile(c.key)
+
+	done := make(chan struct{})
+	var element Element
+
+	go func() {
+
+		defer cancel()
+		defer close(done)
+
+		defer func() {
+			if err := recover(); err != nil {
+				Log.Error("Error rendering asynchronous element '%s': %v", c.key, err)
+			}
+		}()
+
+		element = elementFunction(c)
+	}()
+
+	return func() Element {
+		select {
+		case <-done:
+			return element
+		case <-ctx.Done():
+			// the element might have been finished just now
+			select {
+			case <-done:
+				return element
+			default:
+			}
+			Log.Warning("Asynchronous element '%s' not done: %v", c.key, ctx.Err())
+			return nil
+		}
+	}
+}
+
+// Registers an element that is streamed once it's resolved, see Suspense
+func (d *DefaultContext) Suspend(id string, await PureElementFunction) {
+	d.root.Store.Suspend(d.Ctx(), id, await)
+}
+
+// Executes the element function concurrently with the rest of the page and
+// awaits its result when the element gets rendered
+func Async(c Context, key string, elementFunction ElementFunction) PureElementFunction {
+	return c.AsyncElement(key, elementFunction)
+}
+
 func (d *DefaultContext) Element(key string, elementFunction ElementFunction) Element {
 
 	c := &DefaultContext{
-		key:  fmt.Sprintf("%s.%s", d.key, key),
-		root: d.root,
+		key:   fmt.Sprintf("%s.%s", d.key, key),
+		root:  d.root,
+		async: d.async,
+		ctx:   d.ctx,
+	}
+
+	// we don't track elements executed in other goroutines, as the
+	// order of their occurrence isn't deterministic
+	if c.async {
+		return elementFunction(c)
 	}
 
+	// we record which variables the element reads, so we know whether it's
+	// dirty after a change
+	d.root.Store.resetDependencies(c.key)
+	d.root.Store.PushElement(c.key)
+	defer d.root.Store.PopElement()
+
 	return elementFunction(c)
 
 }
 
 func (d *DefaultContext) Scope(key string) Context {
 	return &DefaultContext{
-		key:  key,
-		root: d.root,
+		key:   key,
+		root:  d.root,
+		async: d.async,
+		ctx:   d.ctx,
 	}
 }
 
 func (d *DefaultContext) Execute(elementFunction ElementFunction) Element {
 	d.root.interactive = true
+	d.root.Store.resetExecuted()
+	d.root.Store.resetDependencies(d.key)
+	d.root.Store.PushElement(d.key)
+	defer d.root.Store.PopElement()
 	return elementFunction(d)
 }
 
@@ -258,6 +664,9 @@ func (d *DefaultContext) GetVar(key string) ContextVarObj {
 }
 
 func (d *DefaultContext) AddFunc(function ContextFuncObj[any], key string) {
+	if d.detached() {
+		return
+	}
 	i := d.root.Store.AddFunc(d.key, function)
 	function.SetId(fmt.Sprintf("%s.%d", d.key, i))
 
@@ -265,6 +674,11 @@ func (d *DefaultContext) AddFunc(function ContextFuncObj[any], key string) {
 
 func (d *DefaultContext) AddVar(variable ContextVarObj, key string) error {
 
+	if d.detached() {
+		// the variable can still be used, but it won't be stored
+		return fmt.Errorf("cannot add variable: %w", d.ctx.Err())
+	}
+
 	global := true
 
 	if key == "" {
@@ -279,3 +693,54 @@ func (d *DefaultContext) AddVar(variable ContextVarObj, key string) error {
 func (d *DefaultContext) Key() string {
 	return d.key
 }
+
+func (d *DefaultContext) Track(id string) {
+	// asynchronous elements are always dirty, so we don't need to track them
+	if d.async {
+		return
+	}
+	d.root.Store.Track(id)
+}
+
+func (d *DefaultContext) Invalidate(id string) {
+	if d.detached() {
+		return
+	}
+	d.root.Store.Invalidate(id)
+}
+
+func (d *DefaultContext) Notify(variable ContextVarObj) {
+	if d.detached() {
+		variable.Notify()
+		return
+	}
+	d.root.Store.Notify(variable)
+}
+
+func (d *DefaultContext) Batch(f func()) {
+	if d.detached() {
+		f()
+		return
+	}
+	d.root.Store.Batch(f)
+}
+
+func (d *DefaultContext) MemoCache() *MemoCache {
+	return d.root.Store.Memo
+}
+
+func (d *DefaultContext) NextIndex(key string) int {
+	return d.root.Store.NextIndex(key)
+}
+
+func (d *DefaultContext) OnFinalize(finalizer func()) {
+	d.root.Store.OnFinalize(finalizer)
+}
+
+// Returns an instance of the service with the given type
+func (d *DefaultContext) Service(t reflect.Type) (any, error) {
+	if d.root.app == nil || d.root.app.Services == nil {
+		return nil, fmt.Errorf("no provider for type %s", t)
+	}
+	return d.root.app.Services.Resolve(d, d.root.Store, t)
+}
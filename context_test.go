@@ -0,0 +1,283 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package gospel
+
+import (
+	"context"
+	"github.com/google/go-cmp/cmp"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAsync(t *testing.T) {
+
+	c := makeTestContext()
+
+	// each panel waits for the other one to start, which only works if
+	// they're executed concurrently
+	started := make(chan string, 2)
+
+	panel := func(text string) ElementFunction {
+		return func(c Context) Element {
+
+			started <- text
+
+			select {
+			case <-time.After(time.Second):
+				t.Errorf("expected concurrent rendering")
+			case other := <-started:
+				// we put it back for the other panel
+				started <- other
+			}
+
+			counter := Var(c, 0)
+			counter.Set(counter.Get() + 1)
+			return Div(text)
+		}
+	}
+
+	root := c.Execute(func(c Context) Element {
+		return Div(
+			Async(c, "first", panel("first")),
+			Async(c, "second", panel("second")),
+		)
+	})
+
+	html := root.(*HTMLElement).RenderElement()
+
+	if html != "<div><div>first</div><div>second</div></div>" {
+		t.Fatalf("unexpected output: %s", html)
+	}
+}
+
+func TestAsyncDeadline(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	r := httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+	c := MakeDefaultContext(r, httptest.NewRecorder(), MakeStore(MakeCookieStore("")))
+
+	running := make(chan struct{})
+	stop := make(chan struct{})
+	done := make(chan struct{})
+
+	var local *VarObj[int]
+	var asyncCtx context.Context
+	var element PureElementFunction
+
+	c.Execute(func(c Context) Element {
+
+		shared := PersistentGlobalVar(c, "shared", 0)
+
+		element = Async(c, "slow", func(ac Context) Element {
+
+			defer close(done)
+
+			asyncCtx = ac.Ctx()
+			close(running)
+
+			// the element ignores the deadline and keeps using the variable
+			for i := 0; ; i++ {
+				select {
+				case <-stop:
+					// it can't add variables to the store anymore
+					local = Var(ac, 1)
+					local.Set(2)
+					return Div("slow")
+				default:
+					shared.Set(i)
+				}
+			}
+		})
+
+		return nil
+	})
+
+	<-running
+	cancel()
+
+	if element() != nil {
+		t.Fatalf("expected no element after the deadline")
+	}
+
+	// the variable is stored while the element is still running
+	c.Store.Finalize()
+
+	if _, ok := c.GetById("shared").GetRaw().(int); !ok {
+		t.Fatalf("expected a value")
+	}
+
+	close(stop)
+	<-done
+
+	if asyncCtx.Err() == nil {
+		t.Fatalf("expected the context of the element to be canceled")
+	}
+
+	if local.Get() != 2 || c.GetById(local.Id()) != nil {
+		t.Fatalf("expected a detached variable")
+	}
+}
+
+func TestDirtyElements(t *testing.T) {
+
+	c := makeTestContext()
+
+	var x, y *VarObj[int]
+
+	c.Execute(func(c Context) Element {
+		x = GlobalVar(c, "x", 1)
+		y = GlobalVar(c, "y", 2)
+		return Div(
+			c.Element("a", func(c Context) Element {
+				return Div(x.Get(), c.Element("nested", func(c Context) Element {
+					return Span(y.Get())
+				}))
+			}),
+			c.Element("b", func(c Context) Element {
+				return Div(y.Get())
+			}),
+			c.Element("static", func(c Context) Element {
+				return Div("static")
+			}),
+			Async(c, "async", func(c Context) Element {
+				return Div(x.Get())
+			}),
+		)
+	})
+
+	dirty := func() []string {
+		keys := []string{}
+		for _, key := range []string{"root.a", "root.a.nested", "root.b", "root.static", "root.async"} {
+			if c.Store.Dirty(key) {
+				keys = append(keys, key)
+			}
+		}
+		return keys
+	}
+
+	// asynchronous elements are always dirty
+	if diff := cmp.Diff([]string{"root.async"}, dirty()); diff != "" {
+		t.Fatalf("unexpected dirty elements before the change: %s", diff)
+	}
+
+	x.Set(3)
+
+	if diff := cmp.Diff([]string{"root.a", "root.async"}, dirty()); diff != "" {
+		t.Fatalf("unexpected dirty elements after changing x: %s", diff)
+	}
+
+	y.Set(4)
+
+	// parents are dirty if one of their children is
+	if diff := cmp.Diff([]string{"root.a", "root.a.nested", "root.b", "root.async"}, dirty()); diff != "" {
+		t.Fatalf("unexpected dirty elements after changing y: %s", diff)
+	}
+
+	if !c.Store.Dirty("root") {
+		t.Fatalf("expected the root to be dirty")
+	}
+}
+
+func TestElementDependencies(t *testing.T) {
+
+	c := makeTestContext()
+
+	var x, y *VarObj[int]
+	readX := true
+
+	render := func() {
+		c.Execute(func(c Context) Element {
+			x = GlobalVar(c, "x", 1)
+			y = GlobalVar(c, "y", 2)
+			return Div(
+				c.Element("a", func(c Context) Element {
+					if readX {
+						return Div(x.Get())
+					}
+					return Div(y.Get())
+				}),
+				// elements with the same key share their dependencies
+				c.Element("item", func(c Context) Element {
+					return Div(x.Get())
+				}),
+				c.Element("item", func(c Context) Element {
+					return Div(y.Get())
+				}),
+			)
+		})
+	}
+
+	render()
+
+	readX = false
+	render()
+
+	if diff := cmp.Diff(map[string]map[string]int{
+		"root.a":    {y.Id(): 0},
+		"root.item": {x.Id(): 0, y.Id(): 0},
+	}, c.Store.Dependencies); diff != "" {
+		t.Fatalf("unexpected dependencies: %s", diff)
+	}
+
+	x.Set(3)
+
+	// the element doesn't read x anymore
+	if c.Store.Dirty("root.a") || !c.Store.Dirty("root.item") {
+		t.Fatalf("expected only the items to be dirty")
+	}
+}
+
+func TestSuspense(t *testing.T) {
+
+	c := makeTestContext()
+
+	root := c.Execute(func(c Context) Element {
+		return Div(
+			Suspense(c, Span("loading"), func(c Context) Element {
+				time.Sleep(50 * time.Millisecond)
+				return Span("slow")
+			}),
+			Suspense(c, Span("loading"), func(c Context) Element {
+				return Span("fast")
+			}),
+		)
+	})
+
+	shell := root.(*HTMLElement).RenderElement()
+
+	expected := `<div><div id="root.suspense.0" style="display: contents"><span>loading</span></div><div id="root.suspense.1" style="display: contents"><span>loading</span></div></div>`
+
+	if shell != expected {
+		t.Fatalf("unexpected shell: %s", shell)
+	}
+
+	var buf strings.Builder
+
+	if err := c.Store.WriteSuspended(context.Background(), &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	streamed := buf.String()
+	fast := strings.Index(streamed, `<template data-gospel-suspense="root.suspense.1"><span>fast</span></template>`)
+	slow := strings.Index(streamed, `<template data-gospel-suspense="root.suspense.0"><span>slow</span></template>`)
+
+	// elements are streamed in the order in which they are resolved
+	if fast == -1 || slow == -1 || fast > slow {
+		t.Fatalf("unexpected stream: %s", streamed)
+	}
+}
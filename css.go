@@ -55,7 +55,15 @@ func Styled(name string, element Element) Element {
 			// we add the rule class to the class names
 			classNames = append(classNames, rule.Class())
 		}
-		element.Attributes = append(element.Attributes, Class(strings.Join(classNames, " ")))
+		classes := strings.Join(classNames, " ")
+		// browsers ignore repeated attributes, so we extend an existing class
+		if class := element.Attribute("class"); class != nil {
+			if strValue, ok := class.Value.(string); ok {
+				class.Value = strValue + " " + classes
+				return
+			}
+		}
+		element.Attributes = append(element.Attributes, Class(classes))
 	}
 
 	// we add all rules to the stylesheet
@@ -82,7 +90,7 @@ func (s *Stylesheet) Styles() Element {
 	}
 
 	return StyleTag(
-		L(ss),
+		Literal(ss),
 	)
 }
 
@@ -0,0 +1,280 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package gospel
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// A URL that we don't sanitize, e.g. for "data:" URLs that we generated
+type SafeURL string
+
+// JavaScript code that we don't escape, e.g. for event handler attributes
+type SafeJS string
+
+// CSS that we don't sanitize, e.g. for style attributes with user data
+type SafeCSS string
+
+const (
+	// replaces URLs with unsafe schemes, like in html/template
+	unsafeURL = "about:invalid#zGospelz"
+	// replaces unsafe CSS, like in html/template
+	unsafeCSS = "ZgotmplZ"
+)
+
+var urlAttributes = map[string]bool{
+	"action":     true,
+	"background": true,
+	"cite":       true,
+	"codebase":   true,
+	"data":       true,
+	"formaction": true,
+	"href":       true,
+	"icon":       true,
+	"longdesc":   true,
+	"manifest":   true,
+	"ping":       true,
+	"poster":     true,
+	"src":        true,
+	"srcset":     true,
+	"xlink:href": true,
+}
+
+var safeSchemes = map[string]bool{
+	"http":   true,
+	"https":  true,
+	"mailto": true,
+	"tel":    true,
+}
+
+func isURLAttribute(name string) bool {
+	return urlAttributes[strings.ToLower(name)]
+}
+
+func isEventAttribute(name string) bool {
+	return len(name) > 2 && strings.HasPrefix(strings.ToLower(name), "on")
+}
+
+// Replaces URLs with schemes other than http(s), mailto and tel. Relative
+// URLs are always allowed.
+func SanitizeURL(url string) string {
+
+	i := strings.IndexAny(url, ":/?#")
+
+	if i == -1 || url[i] != ':' {
+		// this is a relative URL
+		return url
+	}
+
+	// browsers ignore whitespace and control characters in the scheme
+	scheme := strings.Map(func(r rune) rune {
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, url[:i])
+
+	if safeSchemes[strings.ToLower(scheme)] {
+		return url
+	}
+
+	return unsafeURL
+}
+
+// Sanitizes all URLs of a srcset attribute
+func sanitizeSrcset(srcset string) string {
+
+	candidates := strings.Split(srcset, ",")
+
+	for i, candidate := range candidates {
+		fields := strings.Fields(candidate)
+		if len(fields) == 0 {
+			continue
+		}
+		fields[0] = SanitizeURL(fields[0])
+		candidates[i] = strings.Join(fields, " ")
+	}
+
+	return strings.Join(candidates, ", ")
+}
+
+var unsafeCSSPatterns = []string{
+	"<", ">", "\\", "/*", "expression", "javascript:", "vbscript:", "@import", "behavior", "-moz-binding",
+}
+
+// Replaces CSS that could execute code or break out of its context
+func SanitizeCSS(css string) string {
+
+	lower := strings.ToLower(css)
+
+	for _, pattern := range unsafeCSSPatterns {
+		if strings.Contains(lower, pattern) {
+			return unsafeCSS
+		}
+	}
+
+	// we check the URLs of url(...) values
+	for rest := lower; ; {
+
+		i := strings.Index(rest, "url(")
+
+		if i == -1 {
+			break
+		}
+
+		rest = rest[i+4:]
+		end := strings.Index(rest, ")")
+
+		if end == -1 {
+			return unsafeCSS
+		}
+
+		url := strings.Trim(strings.TrimSpace(rest[:end]), `"'`)
+
+		if SanitizeURL(url) == unsafeURL {
+			return unsafeCSS
+		}
+
+		rest = rest[end:]
+	}
+
+	return css
+}
+
+// Turns a value into a JavaScript string literal, so that it can't be
+// executed as code
+func EscapeJSValue(value string) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return `""`
+	}
+	return string(data)
+}
+
+// Escapes a value for the given attribute, returns false if the value
+// can't be rendered. The result still needs to be HTML-escaped.
+func escapeAttributeValue(name string, value any) (string, bool) {
+
+	var strValue string
+	var safe bool
+
+	switch v := value.(type) {
+	case string:
+		strValue = v
+	case SafeURL:
+		strValue, safe = string(v), isURLAttribute(name)
+	case SafeJS:
+		strValue, safe = string(v), isEventAttribute(name)
+	case SafeCSS:
+		strValue, safe = string(v), strings.EqualFold(name, "style")
+	case interface{ String() string }:
+		strValue = v.String()
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		// numeric attributes like 'colspan' or 'min'
+		strValue = fmt.Sprint(v)
+	default:
+		return "", false
+	}
+
+	if safe {
+		return strValue, true
+	}
+
+	switch {
+	case strings.EqualFold(name, "srcset"):
+		return sanitizeSrcset(strValue), true
+	case isURLAttribute(name):
+		return SanitizeURL(strings.TrimSpace(strValue)), true
+	case isEventAttribute(name):
+		return EscapeJSValue(strValue), true
+	case strings.EqualFold(name, "style"):
+		return SanitizeCSS(strValue), true
+	}
+
+	return strValue, true
+}
+
+// Checks whether the text starts with the (lowercase ASCII) prefix, ignoring
+// the case of ASCII letters only, like browsers do when matching end tags
+func hasASCIIPrefixFold(text, prefix string) bool {
+
+	if len(text) < len(prefix) {
+		return false
+	}
+
+	for i := 0; i < len(prefix); i++ {
+		c := text[i]
+		if 'A' <= c && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		if c != prefix[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Escapes sequences that would end a raw text element (like <script>)
+// or change how its content is parsed
+func escapeRawText(tag, text string) string {
+
+	closing := "</" + strings.ToLower(tag)
+
+	if !strings.Contains(text, "<") {
+		return text
+	}
+
+	var b strings.Builder
+
+	// we compare the original bytes, as case mappings of non-ASCII
+	// characters can change the length of the text
+	for i := 0; i < len(text); {
+		if hasASCIIPrefixFold(text[i:], closing) {
+			// "<\/script" is equivalent in JavaScript and CSS strings
+			b.WriteString(`<\/`)
+			b.WriteString(text[i+2 : i+len(closing)])
+			i += len(closing)
+		} else if strings.HasPrefix(text[i:], "<!--") {
+			b.WriteString(`<\!--`)
+			i += 4
+		} else {
+			b.WriteByte(text[i])
+			i++
+		}
+	}
+
+	return b.String()
+}
+
+// Escapes text children of raw text elements for their context. Safe
+// literals (e.g. SafeJS values) are rendered as they are.
+func rawText(element *HTMLElement) {
+	for _, child := range element.Children {
+
+		htmlChild, ok := child.(*HTMLElement)
+
+		if !ok || htmlChild.Safe {
+			continue
+		}
+
+		if strValue, ok := htmlChild.Value.(string); ok {
+			htmlChild.Value = escapeRawText(element.Tag, strValue)
+			htmlChild.Safe = true
+		}
+	}
+}
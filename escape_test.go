@@ -0,0 +1,52 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package gospel
+
+import (
+	"testing"
+)
+
+func TestContextualEscaping(t *testing.T) {
+
+	for _, test := range []struct {
+		element  *HTMLElement
+		expected string
+	}{
+		{A(Href("javascript:alert(1)")), `<a href="about:invalid#zGospelz"></a>`},
+		{A(Href(" Java\tScript:alert(1)")), `<a href="about:invalid#zGospelz"></a>`},
+		{A(Href("https://example.com/?a=1&b=2")), `<a href="https://example.com/?a=1&amp;b=2"></a>`},
+		{A(Href("/relative:path")), `<a href="/relative:path"></a>`},
+		{A(Href(SafeURL("data:text/plain,hi"))), `<a href="data:text/plain,hi"></a>`},
+		{Img(Attrib("srcset")("/a.png 1x, javascript:x 2x")), `<img srcset="/a.png 1x, about:invalid#zGospelz 2x"/>`},
+		{Button(Attrib("onclick")("alert(1)")), `<button onclick="&#34;alert(1)&#34;"></button>`},
+		{Button(Attrib("onclick")(SafeJS("go()"))), `<button onclick="go()"></button>`},
+		{Div(Style("color: red; background: url('/bg.png')")), `<div style="color: red; background: url(&#39;/bg.png&#39;)"></div>`},
+		{Div(Style("background: url(javascript:alert(1))")), `<div style="ZgotmplZ"></div>`},
+		{Div(Style("width: expression(alert(1))")), `<div style="ZgotmplZ"></div>`},
+		{Div(Style(SafeCSS("width: expression(1)"))), `<div style="width: expression(1)"></div>`},
+		{Script("var a = '</script><script>alert(1)</script>';"), `<script>var a = '<\/script><script>alert(1)<\/script>';</script>`},
+		{Script(SafeJS("</script>")), `<script></script></script>`},
+		// non-ASCII case mappings change the length of the text
+		{Script("\u212a</script>"), "<script>\u212a<\\/script></script>"},
+		{Script("İİ</SCRIPT>x"), `<script>İİ<\/SCRIPT>x</script>`},
+		{StyleTag("İ</style><!--"), `<style>İ<\/style><\!--</style>`},
+		{StyleTag("a { content: '</STYLE>' }"), `<style>a { content: '<\/STYLE>' }</style>`},
+		{Div("<b>"), `<div>&lt;b&gt;</div>`},
+	} {
+		if result := test.element.RenderElement(); result != test.expected {
+			t.Errorf("expected '%s', got '%s'", test.expected, result)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package gospel
+
+const (
+	FlashInfo    = "info"
+	FlashSuccess = "success"
+	FlashWarning = "warning"
+	FlashError   = "error"
+)
+
+type FlashMessage struct {
+	Level   string `json:"level"`
+	Message string `json:"message"`
+}
+
+const (
+	flashesKey    = "gospel.flashes"
+	flashStateKey = "gospel.flashes.state"
+)
+
+type flashState struct {
+	// messages from the previous request
+	incoming []FlashMessage
+	// messages from this request
+	outgoing []FlashMessage
+	// the number of outgoing messages that were already displayed
+	displayed int
+	// messages that were displayed in this request
+	shown []FlashMessage
+}
+
+func useFlashState(c Context) *flashState {
+
+	if variable := c.GetVar(flashStateKey); variable != nil {
+		if state, ok := variable.GetRaw().(*flashState); ok {
+			return state
+		}
+	}
+
+	stored := PersistentGlobalVar(c, flashesKey, []FlashMessage{})
+
+	state := &flashState{
+		incoming: stored.Get(),
+	}
+
+	GlobalVar(c, flashStateKey, state)
+
+	// we decide which messages to keep once we know if there's a redirect
+	c.OnFinalize(func() {
+
+		// the page won't be displayed if we redirect, so we keep all new messages
+		kept := state.outgoing[state.displayed:]
+
+		if router := UseRouter(c); router != nil {
+			if _, ok := router.Redirect(); ok {
+				kept = state.outgoing
+			}
+		}
+
+		stored.Set(append([]FlashMessage{}, kept...))
+	})
+
+	return state
+}
+
+// Adds a message that is displayed on the next rendered page, which is
+// usually the one we redirect to
+func Flash(c Context, level, message string) {
+	state := useFlashState(c)
+	state.outgoing = append(state.outgoing, FlashMessage{Level: level, Message: message})
+}
+
+// Returns the flash messages for the current page and consumes them. As
+// elements can be executed repeatedly, we return the same messages for
+// the rest of the request.
+func Flashes(c Context) []FlashMessage {
+	state := useFlashState(c)
+	state.shown = append(state.shown, state.incoming...)
+	state.shown = append(state.shown, state.outgoing[state.displayed:]...)
+	state.incoming = nil
+	state.displayed = len(state.outgoing)
+	return append([]FlashMessage{}, state.shown...)
+}
@@ -0,0 +1,79 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package gospel
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFlashes(t *testing.T) {
+
+	persistentStore := MakeCookieStore("")
+
+	// we simulate requests that share the same session
+	request := func(handler func(c Context, router *Router)) {
+		c := MakeDefaultContext(httptest.NewRequest("GET", "/", nil), httptest.NewRecorder(), MakeStore(persistentStore))
+		router := MakeRouter(c)
+		c.Execute(func(c Context) Element {
+			handler(c, router)
+			return nil
+		})
+		c.Store.Finalize()
+	}
+
+	saved := []FlashMessage{{FlashSuccess, "Saved!"}}
+
+	request(func(c Context, router *Router) {
+		Flash(c, FlashSuccess, "Saved!")
+		// the message isn't displayed on this page
+		Flashes(c)
+		router.RedirectTo("/items")
+	})
+
+	request(func(c Context, router *Router) {
+		if diff := cmp.Diff(saved, Flashes(c)); diff != "" {
+			t.Fatalf("expected the message after the redirect: %s", diff)
+		}
+	})
+
+	request(func(c Context, router *Router) {
+		if flashes := Flashes(c); len(flashes) != 0 {
+			t.Fatalf("expected the message to be consumed: %v", flashes)
+		}
+		Flash(c, FlashInfo, "Not redirected")
+	})
+
+	// messages that weren't displayed yet survive a render
+	request(func(c Context, router *Router) {
+		if flashes := Flashes(c); len(flashes) != 1 || flashes[0].Message != "Not redirected" {
+			t.Fatalf("expected the undisplayed message: %v", flashes)
+		}
+	})
+
+	// GET requests aren't redirected to their own path, so the page is displayed
+	request(func(c Context, router *Router) {
+		Flash(c, FlashInfo, "Same page")
+		Flashes(c)
+		router.RedirectTo("/")
+	})
+
+	request(func(c Context, router *Router) {
+		if flashes := Flashes(c); len(flashes) != 0 {
+			t.Fatalf("expected the message to be consumed: %v", flashes)
+		}
+	})
+}
@@ -0,0 +1,505 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package gospel
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// The version of the graph format, documents with a newer version are
+// rejected by the decoder
+const GraphVersion = 1
+
+// Node types of the graph format
+const (
+	ElementNode   = "element"
+	LiteralNode   = "literal"
+	FunctionNode  = "function"
+	RouteNode     = "route"
+	GeneratorNode = "generator"
+	FuncNode      = "func"
+)
+
+type graphDocument struct {
+	Version int        `json:"version"`
+	Root    *graphNode `json:"root"`
+}
+
+type graphNode struct {
+	Type string `json:"type"`
+	// elements
+	Tag        string            `json:"tag,omitempty"`
+	Namespace  string            `json:"namespace,omitempty"`
+	Void       bool              `json:"void,omitempty"`
+	Attributes []*graphAttribute `json:"attributes,omitempty"`
+	Children   []*graphNode      `json:"children,omitempty"`
+	// literals
+	Value string `json:"value,omitempty"`
+	Safe  bool   `json:"safe,omitempty"`
+	// functions, generators and funcs
+	Name      string           `json:"name,omitempty"`
+	Arguments []*graphArgument `json:"arguments,omitempty"`
+	Result    []*graphNode     `json:"result,omitempty"`
+	Data      json.RawMessage  `json:"data,omitempty"`
+	// routes
+	Route   string     `json:"route,omitempty"`
+	Element *graphNode `json:"element,omitempty"`
+}
+
+type graphAttribute struct {
+	Name   string `json:"name"`
+	Hidden bool   `json:"hidden,omitempty"`
+	// nil for boolean attributes
+	Value any `json:"value,omitempty"`
+	// the kind of a safe value ('url', 'js' or 'css')
+	Safe string   `json:"safe,omitempty"`
+	Args []string `json:"args,omitempty"`
+}
+
+type graphArgument struct {
+	Value any        `json:"value,omitempty"`
+	Node  *graphNode `json:"node,omitempty"`
+}
+
+var graphMutex sync.RWMutex
+var graphTypesByName = map[string]reflect.Type{}
+var graphTypeNames = map[reflect.Type]string{}
+var graphFuncsByName = map[string]any{}
+var graphFuncNames = map[uintptr]string{}
+
+// Registers a generator type under the given name, so that it can be
+// stored in a graph. Values are serialized with encoding/json.
+func RegisterGraphType[T Generator](name string) {
+	graphMutex.Lock()
+	defer graphMutex.Unlock()
+
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	graphTypesByName[name] = t
+	graphTypeNames[t] = name
+}
+
+// Registers a function (e.g. an element function of a route) under the
+// given name, so that it can be stored in a graph. Functions are identified
+// by their code, so closures created by the same function can't be told
+// apart and should not be registered.
+func RegisterGraphFunc(name string, f any) error {
+
+	value := reflect.ValueOf(f)
+
+	if value.Kind() != reflect.Func {
+		return fmt.Errorf("not a function")
+	}
+
+	graphMutex.Lock()
+	defer graphMutex.Unlock()
+
+	graphFuncsByName[name] = f
+	graphFuncNames[value.Pointer()] = name
+
+	return nil
+}
+
+func MustRegisterGraphFunc(name string, f any) {
+	if err := RegisterGraphFunc(name, f); err != nil {
+		panic(err)
+	}
+}
+
+// Serializes an element tree (or a function or route) to JSON. Functions
+// and generators that aren't part of gospel have to be registered first.
+func MarshalGraph(value any) ([]byte, error) {
+
+	root, err := encodeGraphNode(value)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(&graphDocument{
+		Version: GraphVersion,
+		Root:    root,
+	})
+}
+
+// Deserializes a graph produced by MarshalGraph. Elements with registered
+// HTML tags are created with their tag function, so decorators apply again.
+func UnmarshalGraph(data []byte) (any, error) {
+
+	var document graphDocument
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// numeric attribute values are kept as they are
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&document); err != nil {
+		return nil, fmt.Errorf("cannot decode graph: %w", err)
+	}
+
+	if document.Version < 1 || document.Version > GraphVersion {
+		return nil, fmt.Errorf("unsupported graph version %d", document.Version)
+	}
+
+	if document.Root == nil {
+		return nil, fmt.Errorf("graph has no root")
+	}
+
+	return decodeGraphNode(document.Root)
+}
+
+func encodeGraphNodes(values []any) ([]*graphNode, error) {
+
+	nodes := make([]*graphNode, 0, len(values))
+
+	for _, value := range values {
+
+		if value == nil {
+			continue
+		}
+
+		if node, err := encodeGraphNode(value); err != nil {
+			return nil, err
+		} else {
+			nodes = append(nodes, node)
+		}
+	}
+
+	return nodes, nil
+}
+
+func encodeGraphNode(value any) (*graphNode, error) {
+
+	switch v := value.(type) {
+	case *HTMLElement:
+		return encodeGraphElement(v)
+	case *Function:
+		return encodeGraphFunction(v)
+	case *RouteConfig:
+
+		element, err := encodeGraphNode(v.ElementFunc)
+
+		if err != nil {
+			return nil, fmt.Errorf("route '%s': %w", v.Route, err)
+		}
+
+		return &graphNode{Type: RouteNode, Route: v.Route, Element: element}, nil
+	}
+
+	rv := reflect.ValueOf(value)
+
+	if rv.Kind() == reflect.Func {
+
+		graphMutex.RLock()
+		name, ok := graphFuncNames[rv.Pointer()]
+		graphMutex.RUnlock()
+
+		if !ok {
+			return nil, fmt.Errorf("function of type %T is not registered", value)
+		}
+
+		return &graphNode{Type: FuncNode, Name: name}, nil
+	}
+
+	if _, ok := value.(Generator); ok {
+
+		graphMutex.RLock()
+		name, ok := graphTypeNames[rv.Type()]
+		graphMutex.RUnlock()
+
+		if !ok {
+			return nil, fmt.Errorf("generator type %T is not registered", value)
+		}
+
+		data, err := json.Marshal(value)
+
+		if err != nil {
+			return nil, fmt.Errorf("cannot encode generator '%s': %w", name, err)
+		}
+
+		return &graphNode{Type: GeneratorNode, Name: name, Data: data}, nil
+	}
+
+	return nil, fmt.Errorf("cannot serialize value of type %T", value)
+}
+
+func encodeGraphElement(h *HTMLElement) (*graphNode, error) {
+
+	if h == nil {
+		return nil, fmt.Errorf("cannot serialize a nil element")
+	}
+
+	if strValue, ok := h.Value.(string); ok {
+		return &graphNode{Type: LiteralNode, Value: strValue, Safe: h.Safe}, nil
+	}
+
+	node := &graphNode{
+		Type:      ElementNode,
+		Tag:       h.Tag,
+		Namespace: h.Namespace,
+		Void:      h.Void,
+	}
+
+	for _, attribute := range h.Attributes {
+		if encoded, err := encodeGraphAttribute(attribute); err != nil {
+			return nil, fmt.Errorf("<%s>: %w", h.Tag, err)
+		} else {
+			node.Attributes = append(node.Attributes, encoded)
+		}
+	}
+
+	if children, err := encodeGraphNodes(h.Children); err != nil {
+		return nil, err
+	} else {
+		node.Children = children
+	}
+
+	return node, nil
+}
+
+func encodeGraphAttribute(a *HTMLAttribute) (*graphAttribute, error) {
+
+	attribute := &graphAttribute{
+		Name:   a.Name,
+		Hidden: a.Hidden,
+	}
+
+	switch v := a.Value.(type) {
+	case nil:
+	case SafeURL:
+		attribute.Value, attribute.Safe = string(v), "url"
+	case SafeJS:
+		attribute.Value, attribute.Safe = string(v), "js"
+	case SafeCSS:
+		attribute.Value, attribute.Safe = string(v), "css"
+	case string, json.Number, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		attribute.Value = v
+	default:
+		// e.g. variables and functions, which are bound to a context
+		return nil, fmt.Errorf("cannot serialize value of attribute '%s' (%T)", a.Name, a.Value)
+	}
+
+	for _, arg := range a.Args {
+		if strArg, ok := arg.(string); ok {
+			attribute.Args = append(attribute.Args, strArg)
+		}
+	}
+
+	return attribute, nil
+}
+
+func encodeGraphFunction(f *Function) (*graphNode, error) {
+
+	node := &graphNode{Type: FunctionNode, Name: f.Name}
+
+	for _, argument := range f.Arguments {
+
+		if _, ok := argument.Value.(string); ok {
+			node.Arguments = append(node.Arguments, &graphArgument{Value: argument.Value})
+			continue
+		}
+
+		if argNode, err := encodeGraphNode(argument.Value); err != nil {
+			return nil, fmt.Errorf("argument of '%s': %w", f.Name, err)
+		} else {
+			node.Arguments = append(node.Arguments, &graphArgument{Node: argNode})
+		}
+	}
+
+	var err error
+
+	if node.Children, err = encodeGraphNodes(f.Children); err != nil {
+		return nil, err
+	}
+
+	// we store the result so we don't need the macro to decode the graph
+	if node.Result, err = encodeGraphNodes(f.Result); err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+func decodeGraphNodes(nodes []*graphNode) ([]any, error) {
+
+	values := make([]any, 0, len(nodes))
+
+	for _, node := range nodes {
+		if value, err := decodeGraphNode(node); err != nil {
+			return nil, err
+		} else {
+			values = append(values, value)
+		}
+	}
+
+	return values, nil
+}
+
+func decodeGraphNode(node *graphNode) (any, error) {
+
+	if node == nil {
+		return nil, fmt.Errorf("missing node")
+	}
+
+	switch node.Type {
+	case LiteralNode:
+		if node.Safe {
+			return SafeLiteral(node.Value), nil
+		}
+		return Literal(node.Value), nil
+	case ElementNode:
+		return decodeGraphElement(node)
+	case FunctionNode:
+		return decodeGraphFunction(node)
+	case RouteNode:
+
+		element, err := decodeGraphNode(node.Element)
+
+		if err != nil {
+			return nil, fmt.Errorf("route '%s': %w", node.Route, err)
+		}
+
+		return &RouteConfig{Route: node.Route, ElementFunc: element}, nil
+	case FuncNode:
+
+		graphMutex.RLock()
+		f, ok := graphFuncsByName[node.Name]
+		graphMutex.RUnlock()
+
+		if !ok {
+			return nil, fmt.Errorf("unknown function '%s'", node.Name)
+		}
+
+		return f, nil
+	case GeneratorNode:
+		return decodeGraphGenerator(node)
+	}
+
+	return nil, fmt.Errorf("unknown node type '%s'", node.Type)
+}
+
+func decodeGraphElement(node *graphNode) (*HTMLElement, error) {
+
+	children, err := decodeGraphNodes(node.Children)
+
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := make([]*HTMLAttribute, 0, len(node.Attributes))
+
+	for _, a := range node.Attributes {
+
+		attribute := &HTMLAttribute{Name: a.Name, Hidden: a.Hidden, Value: a.Value}
+
+		if strValue, ok := a.Value.(string); ok {
+			switch a.Safe {
+			case "url":
+				attribute.Value = SafeURL(strValue)
+			case "js":
+				attribute.Value = SafeJS(strValue)
+			case "css":
+				attribute.Value = SafeCSS(strValue)
+			}
+		}
+
+		for _, arg := range a.Args {
+			attribute.Args = append(attribute.Args, arg)
+		}
+
+		attributes = append(attributes, attribute)
+	}
+
+	if node.Namespace == "" && node.Tag != "" {
+		if tag, ok := elements[node.Tag]; ok {
+			args := children
+			for _, attribute := range attributes {
+				args = append(args, attribute)
+			}
+			// the tag function adds the decorators of the element
+			return tag(args...), nil
+		}
+	}
+
+	return &HTMLElement{
+		Tag:        node.Tag,
+		Namespace:  node.Namespace,
+		Void:       node.Void,
+		Children:   children,
+		Attributes: attributes,
+	}, nil
+}
+
+func decodeGraphFunction(node *graphNode) (*Function, error) {
+
+	f := &Function{Name: node.Name}
+
+	for _, argument := range node.Arguments {
+
+		if argument.Node == nil {
+			f.Arguments = append(f.Arguments, &FunctionArgument{Value: argument.Value})
+			continue
+		}
+
+		if value, err := decodeGraphNode(argument.Node); err != nil {
+			return nil, fmt.Errorf("argument of '%s': %w", node.Name, err)
+		} else {
+			f.Arguments = append(f.Arguments, &FunctionArgument{Value: value})
+		}
+	}
+
+	var err error
+
+	if f.Children, err = decodeGraphNodes(node.Children); err != nil {
+		return nil, err
+	}
+
+	if f.Result, err = decodeGraphNodes(node.Result); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func decodeGraphGenerator(node *graphNode) (any, error) {
+
+	graphMutex.RLock()
+	t, ok := graphTypesByName[node.Name]
+	graphMutex.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown generator type '%s'", node.Name)
+	}
+
+	var value reflect.Value
+
+	if t.Kind() == reflect.Pointer {
+		value = reflect.New(t.Elem())
+	} else {
+		value = reflect.New(t)
+	}
+
+	if err := json.Unmarshal(node.Data, value.Interface()); err != nil {
+		return nil, fmt.Errorf("cannot decode generator '%s': %w", node.Name, err)
+	}
+
+	if t.Kind() == reflect.Pointer {
+		return value.Interface(), nil
+	}
+
+	return value.Elem().Interface(), nil
+}
@@ -0,0 +1,99 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package gospel
+
+import (
+	"strings"
+	"testing"
+)
+
+type greeting struct {
+	Name string `json:"name"`
+}
+
+func (g *greeting) Generate(c Context) (any, error) {
+	return P("Hello ", g.Name), nil
+}
+
+func (g *greeting) RenderCode() string {
+	return ""
+}
+
+func graphPage(c Context) Element {
+	return Div("page")
+}
+
+func TestGraph(t *testing.T) {
+
+	RegisterGraphType[*greeting]("greeting")
+	MustRegisterGraphFunc("page", graphPage)
+
+	tree := Div(
+		Class("box"),
+		A(Href(SafeURL("javascript:void(0)")), "link"),
+		Input(Type("checkbox"), CheckedAttr()),
+		Td(Colspan(2)),
+		SafeLiteral("<b>safe</b> &amp; <i>unsafe</i>"),
+		&greeting{Name: "gospel"},
+		F(Route("/page", graphPage)),
+	)
+
+	data, err := MarshalGraph(tree)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	value, err := UnmarshalGraph(data)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, ok := value.(*HTMLElement)
+
+	if !ok {
+		t.Fatalf("expected an element, got %T", value)
+	}
+
+	if decoded.RenderElement() != tree.RenderElement() {
+		t.Fatalf("expected %s, got %s", tree.RenderElement(), decoded.RenderElement())
+	}
+
+	// tags are created with their tag function again
+	if link := decoded.Children[0].(*HTMLElement); len(link.Decorators) != 1 {
+		t.Fatalf("expected the decorator of <a>")
+	}
+
+	if g := decoded.Children[4].(*greeting); g.Name != "gospel" {
+		t.Fatalf("expected the generator to be restored, got %v", g)
+	}
+
+	route := decoded.Children[5].(*HTMLElement).Children[0].(*RouteConfig)
+
+	if element := route.ElementFunc.(func(Context) Element)(nil); element.RenderElement() != "<div>page</div>" {
+		t.Fatalf("expected the registered function")
+	}
+
+	// values bound to a context can't be serialized
+	if _, err := MarshalGraph(Div(OnClick(func() {}))); err == nil {
+		t.Fatalf("expected an error")
+	}
+
+	// newer versions are rejected
+	if _, err := UnmarshalGraph([]byte(strings.Replace(string(data), `"version":1`, `"version":2`, 1))); err == nil {
+		t.Fatalf("expected a version error")
+	}
+}
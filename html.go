@@ -37,6 +37,7 @@ type HTMLElement struct {
 	Void       bool                   `json:"void"`
 	Value      any                    `json:"value"`
 	Safe       bool                   `json:"safe"`
+	Namespace  string                 `json:"namespace,omitempty"`
 	Children   []any                  `json:"children" graph:"include"`
 	Attributes []*HTMLAttribute       `json:"attributes" graph:"include"`
 	Args       []any                  `json:"args" graph:"ignore"`
@@ -170,6 +171,7 @@ func (h *HTMLElement) Copy() *HTMLElement {
 		Void:       h.Void,
 		Value:      h.Value,
 		Safe:       h.Safe,
+		Namespace:  h.Namespace,
 		Children:   newChildren,
 		Attributes: newAttributes,
 		Decorators: newDecorators,
@@ -177,6 +179,32 @@ func (h *HTMLElement) Copy() *HTMLElement {
 	}
 }
 
+// Copies the element together with its attributes and (HTML) children, so
+// that the copy can be modified without changing the original
+func (h *HTMLElement) DeepCopy() *HTMLElement {
+
+	el := h.Copy()
+
+	for i, attribute := range el.Attributes {
+		if attribute != nil {
+			el.Attributes[i] = &HTMLAttribute{
+				Name:   attribute.Name,
+				Hidden: attribute.Hidden,
+				Value:  attribute.Value,
+				Args:   append([]any(nil), attribute.Args...),
+			}
+		}
+	}
+
+	for i, child := range el.Children {
+		if htmlChild, ok := child.(*HTMLElement); ok && htmlChild != nil {
+			el.Children[i] = htmlChild.DeepCopy()
+		}
+	}
+
+	return el
+}
+
 type HTMLAttribute struct {
 	Name   string
 	Hidden bool
@@ -242,6 +270,22 @@ func (a *HTMLAttribute) RenderAttribute() string {
 		return ""
 	}
 
+	if a.Value == nil {
+		return fmt.Sprintf("%s", html.EscapeString(a.Name))
+	}
+
+	value, ok := a.escapedValue()
+
+	if !ok {
+		return ""
+	}
+
+	return fmt.Sprintf("%s=\"%s\"", html.EscapeString(a.Name), value)
+}
+
+// Returns the escaped value of the attribute, including its arguments
+func (a *HTMLAttribute) escapedValue() (string, bool) {
+
 	extraArgs := ""
 
 	if len(a.Args) > 0 {
@@ -254,22 +298,14 @@ func (a *HTMLAttribute) RenderAttribute() string {
 
 	}
 
-	if a.Value == nil {
-		return fmt.Sprintf("%s", html.EscapeString(a.Name))
-	}
-
-	strValue, ok := a.Value.(string)
+	// we escape the value for the context of the attribute (e.g. URLs)
+	strValue, ok := escapeAttributeValue(a.Name, a.Value)
 
 	if !ok {
-
-		if stringer, ok := a.Value.(fmt.Stringer); !ok {
-			return ""
-		} else {
-			strValue = stringer.String()
-		}
+		return "", false
 	}
 
-	return fmt.Sprintf("%s=\"%s%s\"", html.EscapeString(a.Name), html.EscapeString(strValue), extraArgs)
+	return html.EscapeString(strValue) + extraArgs, true
 }
 
 func (h *HTMLElement) RenderElement() string {
@@ -307,6 +343,12 @@ func (h *HTMLElement) RenderElement() string {
 			return renderedChildren
 		}
 
+		// in contrast to HTML elements, empty foreign elements (e.g. SVG)
+		// can be self-closing
+		if h.Namespace != "" && renderedChildren == "" {
+			return fmt.Sprintf("<%[1]s%[2]s/>", h.Tag, renderedAttributes)
+		}
+
 		return fmt.Sprintf("<%[1]s%[3]s>%[2]s</%[1]s>", h.Tag, renderedChildren, renderedAttributes)
 	}
 
@@ -386,6 +428,10 @@ func children(args ...any) (chldr []any) {
 			chldr = append(chldr, elem)
 		} else if str, ok := arg.(string); ok {
 			chldr = append(chldr, Literal(str))
+		} else if js, ok := arg.(SafeJS); ok {
+			chldr = append(chldr, SafeLiteral(string(js)))
+		} else if css, ok := arg.(SafeCSS); ok {
+			chldr = append(chldr, SafeLiteral(string(css)))
 		} else if _, ok := arg.(PureElementFunction); ok {
 			chldr = append(chldr, arg)
 		} else if _, ok := arg.(Generator); ok {
@@ -718,12 +764,7 @@ func Submittable() HTMLElementDecorator {
 
 				if req.Method == method && c.Interactive() {
 
-					if HasContentType(req, "multipart/form-data") {
-						if err := req.ParseMultipartForm(1024 * 1024 * 10); err != nil {
-							Log.Error("Cannot parse form: %v", err)
-							return nil
-						}
-					} else if err := req.ParseForm(); err != nil {
+					if err := parseForm(req); err != nil {
 						Log.Error("Cannot parse form: %v", err)
 						return nil
 					}
@@ -762,6 +803,80 @@ func Submittable() HTMLElementDecorator {
 	}
 }
 
+// Name of the form field that identifies the event type of a handler call
+const eventField = "_gspl_event"
+
+func parseForm(req *http.Request) error {
+	if HasContentType(req, "multipart/form-data") {
+		return req.ParseMultipartForm(1024 * 1024 * 10)
+	}
+	return req.ParseForm()
+}
+
+// Maps an event attribute that carries a function to a `gospel-<attribute>`
+// marker, which the client uses to call the function on the server.
+func eventHandler(event, attribute string) HTMLElementDecorator {
+	return func(element *HTMLElement) {
+
+		eventMapper := func(htmlAttrib *HTMLAttribute) []*HTMLAttribute {
+
+			if htmlAttrib.Name != attribute {
+				return []*HTMLAttribute{htmlAttrib}
+			}
+
+			f, ok := htmlAttrib.Value.(ContextFuncObj[any])
+
+			if !ok {
+				// this is a regular (client-side) event handler
+				return []*HTMLAttribute{htmlAttrib}
+			}
+
+			c := f.Context()
+			req := c.Request()
+
+			if req.Method == http.MethodPost && c.Interactive() {
+
+				if err := parseForm(req); err != nil {
+					Log.Error("Cannot parse form: %v", err)
+					return nil
+				}
+
+				if req.Form.Get("_gspl") == f.Id() && req.Form.Get(eventField) == event {
+
+					// we update the variables of the element itself and its children
+					assignVars(c, req.Form, F(element))
+
+					f.Call()
+
+					// we redirect to the current page so the client receives
+					// an up-to-date version of it (unless the function redirects)
+					if router := UseRouter(c); router != nil && router.RedirectedTo() == "" {
+						router.redirectedTo = req.URL.RequestURI()
+					}
+				}
+			}
+
+			return []*HTMLAttribute{&HTMLAttribute{
+				Name:  "gospel-" + attribute,
+				Value: f.Id(),
+			}}
+		}
+
+		element.Attributes = mapHTMLAttributes(element.Attributes, eventMapper)
+	}
+}
+
+// Calls a function given via `OnClick` on the server when the element is clicked
+func Clickable() HTMLElementDecorator {
+	return eventHandler("click", "onClick")
+}
+
+// Calls a function given via `OnChange` on the server when the value of the
+// element changes, after updating the variable assigned to the element
+func Changeable() HTMLElementDecorator {
+	return eventHandler("change", "onChange")
+}
+
 func F(args ...any) *HTMLElement {
 	return &HTMLElement{
 		Children: children(args...),
@@ -769,10 +884,15 @@ func F(args ...any) *HTMLElement {
 }
 
 func makeTag(tag string, args []any, void bool, decorators []HTMLElementDecorator) *HTMLElement {
+	return makeNamespacedTag("", tag, args, void, decorators)
+}
+
+func makeNamespacedTag(namespace, tag string, args []any, void bool, decorators []HTMLElementDecorator) *HTMLElement {
 
 	element := &HTMLElement{
 		Tag:        tag,
 		Void:       void,
+		Namespace:  namespace,
 		Children:   children(args...),
 		Attributes: attributes(args...),
 		Decorators: decorators,
@@ -788,20 +908,10 @@ func makeTag(tag string, args []any, void bool, decorators []HTMLElementDecorato
 
 }
 
-// Marks children of a script node as safe so that we don't escape special characters...
+// We don't HTML-escape the content of script and style nodes, but we
+// make sure it can't break out of the node
 func isScript(element *HTMLElement) {
-	for _, child := range element.Children {
-
-		htmlChild, ok := child.(*HTMLElement)
-
-		if !ok {
-			continue
-		}
-
-		if htmlChild.Value != "" {
-			htmlChild.Safe = true
-		}
-	}
+	rawText(element)
 }
 
 type ElementsMap map[string]func(args ...any) *HTMLElement
@@ -816,143 +926,98 @@ func Tag(tag string, decorators ...HTMLElementDecorator) func(args ...any) *HTML
 }
 
 func VoidTag(tag string, decorators ...HTMLElementDecorator) func(args ...any) *HTMLElement {
-	return func(args ...any) *HTMLElement {
+	elements[tag] = func(args ...any) *HTMLElement {
 		return makeTag(tag, args, true, decorators)
 	}
+	return elements[tag]
+}
+
+const (
+	SVGNamespace    = "http://www.w3.org/2000/svg"
+	MathMLNamespace = "http://www.w3.org/1998/Math/MathML"
+	XLinkNamespace  = "http://www.w3.org/1999/xlink"
+)
+
+// Returns a tag of a foreign namespace like SVG or MathML. Foreign tags
+// aren't registered, as their names can clash with HTML tags (e.g. 'a').
+func ForeignTag(namespace, tag string, decorators ...HTMLElementDecorator) func(args ...any) *HTMLElement {
+	return func(args ...any) *HTMLElement {
+		return makeNamespacedTag(namespace, tag, args, false, decorators)
+	}
 }
 
-// HTML Attributes
-
-var Role = Attrib("role")
-var Lang = Attrib("lang")
-var Charset = Attrib("charset")
-var Rel = Attrib("rel")
-var Sizes = Attrib("sizes")
-var Href = Attrib("href")
-var Type = Attrib("type")
-var Class = Attrib("class")
-var Id = Attrib("id")
-var Src = Attrib("src")
+// Adds an 'xmlns' attribute with the namespace of the element, which is
+// required for the root element of standalone (e.g. SVG) documents
+func DeclareNamespace() HTMLElementDecorator {
+	return func(element *HTMLElement) {
+		if element.Namespace == "" || element.Attribute("xmlns") != nil {
+			return
+		}
+		element.Attributes = append(element.Attributes, &HTMLAttribute{
+			Name:  "xmlns",
+			Value: element.Namespace,
+		})
+	}
+}
+
+// HTML tags and attributes are generated from cmd/htmlgen/spec.go
+
+//go:generate go run ./cmd/htmlgen -out html_catalog.go
+
+type TagInfo struct {
+	GoName string
+	Void   bool
+}
+
+type AttributeInfo struct {
+	GoName  string
+	Boolean bool
+	// allowed values of enumerated attributes
+	Values []string
+	// the elements the attribute applies to (nil for global attributes)
+	Elements []string
+}
+
+// Like Attrib, but warns about values that aren't allowed for the attribute
+func EnumeratedAttrib(tag string, values ...string) func(value any, args ...any) *HTMLAttribute {
+	attrib := Attrib(tag)
+	return func(value any, args ...any) *HTMLAttribute {
+		if strValue, ok := value.(string); ok && !containsFold(values, strValue) {
+			Log.Warning("'%s' is not a valid value for the '%s' attribute", strValue, tag)
+		}
+		return attrib(value, args...)
+	}
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		// enumerated attributes are case-insensitive
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Special attributes
+
 var OnClick = Attrib("onClick")
 var OnChange = Attrib("onChange")
 var OnSubmit = Attrib("onSubmit")
-var Name = Attrib("name")
-var Value = Attrib("value")
 var GospelValue = Attrib("gospel-value")
-var Style = Attrib("style")
-var Method = Attrib("method")
-var Content = Attrib("content")
-var Alt = Attrib("alt")
-var As = Attrib("as")
-var Enctype = Attrib("enctype")
-var Placeholder = Attrib("placeholder")
-var Defer = BooleanAttrib("defer")
-var Download = BooleanAttrib("download")
 var Aria = func(tag string, value any, args ...any) *HTMLAttribute {
 	return Attrib(tag)(value, args...)
 }
 
-// HTML Tags
-// https://www.w3.org/TR/2011/WD-html-markup-20110113/syntax.html
-
-var Html = Tag("html")
-var Div = Tag("div")
-var Title = Tag("title")
-var Head = Tag("head")
-var Body = Tag("body")
-var StyleTag = Tag("style") // renamed as it clashes with 'Style' helper
-var Address = Tag("address")
-var Aside = Tag("aside")
-var Header = Tag("header")
-var Footer = Tag("footer")
-var H1 = Tag("h1")
-var H2 = Tag("h2")
-var H3 = Tag("h3")
-var H4 = Tag("h4")
-var H5 = Tag("h5")
-var H6 = Tag("h6")
-var Main = Tag("main")
-var Nav = Tag("nav")
-var Section = Tag("section")
-var Blockquote = Tag("blockquote")
-var Dd = Tag("dd")
-var Dl = Tag("dl")
-var Dt = Tag("dt")
-var Figcaption = Tag("figcaption")
-var Figure = Tag("figure")
-var Li = Tag("li")
-var Menu = Tag("menu")
-var Ol = Tag("ol")
-var P = Tag("p")
-var Pre = Tag("pre")
-var Ul = Tag("ul")
-var A = Tag("a")
-var Abbr = Tag("abbr")
-var B = Tag("b")
-var Bdi = Tag("bdi")
-var Bdo = Tag("bdo")
-var Cite = Tag("cite")
-var Code = Tag("code")
-var Data = Tag("data")
-var Dfn = Tag("dfn")
-var EmTag = Tag("em")
-var I = Tag("i")
-var Kbd = Tag("kbd")
-var Mark = Tag("mark")
-var G = Tag("g")
-var Rp = Tag("rp")
-var Rt = Tag("rt")
-var Ruby = Tag("ruby")
-var S = Tag("s")
-var Samp = Tag("samp")
-var Small = Tag("small")
-var Span = Tag("span")
-var Strong = Tag("strong")
-var SubTag = Tag("sub")
-var Sup = Tag("sup")
-var Time = Tag("time")
-var U = Tag("u")
-var VarTag = Tag("var") // renamed as it clashes with 'Var'
-var Map = Tag("map")
-var Video = Tag("video")
-var Iframe = Tag("iframe")
-var Object = Tag("object")
-var Picture = Tag("picture")
-var Portal = Tag("portal")
-var Svg = Tag("svg")
-var Math = Tag("math")
-var Canvas = Tag("canvas")
-var Noscript = Tag("noscript")
-var Script = Tag("script", isScript)
-var Del = Tag("del")
-var Ins = Tag("ins")
-var Caption = Tag("caption")
-var Colgroup = Tag("colgroup")
-var Table = Tag("table")
-var Tbody = Tag("tbody")
-var Td = Tag("td")
-var Tfoot = Tag("tfoot")
-var Th = Tag("th")
-var Thead = Tag("thead")
-var Tr = Tag("tr")
-var Button = Tag("button", Assignable(false))
-var Datalist = Tag("datalist")
-var Fieldset = Tag("fieldset")
-var Form = Tag("form", Submittable())
-var Label = Tag("label")
-var Legend = Tag("legend")
-var Meter = Tag("meter")
-var Optgroup = Tag("optgroup")
-var Option = Tag("option")
-var Output = Tag("output")
-var Progress = Tag("progress")
-var Textarea = Tag("textarea", Assignable(true))
-var Details = Tag("details")
-var Dialog = Tag("dialog")
-var Summary = Tag("summary")
-var Slot = Tag("slot")
-var Template = Tag("template")
-var Select = Tag("select", Selectable())
+// Foreign elements
+
+// see the svg and mathml packages for the complete element sets
+var Svg = ForeignTag(SVGNamespace, "svg", DeclareNamespace())
+var Math = ForeignTag(MathMLNamespace, "math", DeclareNamespace())
+var G = ForeignTag(SVGNamespace, "g")
+
+// Special tags
+
 var LiteralTag = Tag("")
 
 // Safe values
@@ -962,26 +1027,6 @@ var L = func(literal string) *HTMLElement {
 	return SafeLiteral(literal)
 }
 
-// HTML Void Tags
-
-var Area = VoidTag("area")
-var Base = VoidTag("base")
-var Br = VoidTag("br")
-var Col = VoidTag("col")
-var Command = VoidTag("command")
-var Embed = VoidTag("embed")
-var Hr = VoidTag("hr")
-var Img = VoidTag("img")
-var Input = VoidTag("input", Assignable(false))
-var Keygen = VoidTag("keygen")
-var Link = VoidTag("link")
-var Meta = VoidTag("meta")
-var Param = VoidTag("param")
-var Source = VoidTag("source")
-var Track = VoidTag("track")
-var Wbr = VoidTag("wbr")
-
-// Special tags
 var Doctype = func(doctype string) *HTMLElement {
 	return &HTMLElement{Safe: true, Value: fmt.Sprintf("<!doctype %s>", doctype)}
 }
@@ -0,0 +1,864 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+// Code generated by cmd/htmlgen. DO NOT EDIT.
+
+package gospel
+
+// HTML Tags
+// https://html.spec.whatwg.org/multipage/indices.html#elements-3
+
+var Html = Tag("html")
+var Head = Tag("head")
+var Title = Tag("title")
+var Base = VoidTag("base")
+var Link = VoidTag("link")
+var Meta = VoidTag("meta")
+var StyleTag = Tag("style", isScript)
+var Body = Tag("body")
+var Article = Tag("article")
+var Section = Tag("section")
+var Nav = Tag("nav")
+var Aside = Tag("aside")
+var H1 = Tag("h1")
+var H2 = Tag("h2")
+var H3 = Tag("h3")
+var H4 = Tag("h4")
+var H5 = Tag("h5")
+var H6 = Tag("h6")
+var Hgroup = Tag("hgroup")
+var Header = Tag("header")
+var Footer = Tag("footer")
+var Address = Tag("address")
+var P = Tag("p")
+var Hr = VoidTag("hr")
+var Pre = Tag("pre")
+var Blockquote = Tag("blockquote")
+var Ol = Tag("ol")
+var Ul = Tag("ul")
+var Menu = Tag("menu")
+var Li = Tag("li")
+var Dl = Tag("dl")
+var Dt = Tag("dt")
+var Dd = Tag("dd")
+var Figure = Tag("figure")
+var Figcaption = Tag("figcaption")
+var Main = Tag("main")
+var Search = Tag("search")
+var Div = Tag("div")
+var A = Tag("a", Clickable())
+var EmTag = Tag("em")
+var Strong = Tag("strong")
+var Small = Tag("small")
+var S = Tag("s")
+var Cite = Tag("cite")
+var Q = Tag("q")
+var Dfn = Tag("dfn")
+var Abbr = Tag("abbr")
+var Ruby = Tag("ruby")
+var Rt = Tag("rt")
+var Rp = Tag("rp")
+var Data = Tag("data")
+var Time = Tag("time")
+var Code = Tag("code")
+var VarTag = Tag("var")
+var Samp = Tag("samp")
+var Kbd = Tag("kbd")
+var SubTag = Tag("sub")
+var Sup = Tag("sup")
+var I = Tag("i")
+var B = Tag("b")
+var U = Tag("u")
+var Mark = Tag("mark")
+var Bdi = Tag("bdi")
+var Bdo = Tag("bdo")
+var Span = Tag("span")
+var Br = VoidTag("br")
+var Wbr = VoidTag("wbr")
+var Ins = Tag("ins")
+var Del = Tag("del")
+var Picture = Tag("picture")
+var Source = VoidTag("source")
+var Img = VoidTag("img")
+var Iframe = Tag("iframe")
+var Embed = VoidTag("embed")
+var Object = Tag("object")
+var Video = Tag("video")
+var Audio = Tag("audio")
+var Track = VoidTag("track")
+var Map = Tag("map")
+var Area = VoidTag("area")
+var Table = Tag("table")
+var Caption = Tag("caption")
+var Colgroup = Tag("colgroup")
+var Col = VoidTag("col")
+var Tbody = Tag("tbody")
+var Thead = Tag("thead")
+var Tfoot = Tag("tfoot")
+var Tr = Tag("tr")
+var Td = Tag("td")
+var Th = Tag("th")
+var Form = Tag("form", Submittable())
+var Label = Tag("label")
+var Input = VoidTag("input", Assignable(false), Clickable(), Changeable())
+var Button = Tag("button", Assignable(false), Clickable())
+var Select = Tag("select", Selectable(), Changeable())
+var Datalist = Tag("datalist")
+var Optgroup = Tag("optgroup")
+var Option = Tag("option")
+var Textarea = Tag("textarea", Assignable(true), Changeable())
+var Output = Tag("output")
+var Progress = Tag("progress")
+var Meter = Tag("meter")
+var Fieldset = Tag("fieldset")
+var Legend = Tag("legend")
+var Details = Tag("details")
+var Summary = Tag("summary")
+var Dialog = Tag("dialog")
+var Script = Tag("script", isScript)
+var Noscript = Tag("noscript")
+var Template = Tag("template")
+var Slot = Tag("slot")
+var Canvas = Tag("canvas")
+
+// Deprecated: <command> is obsolete.
+var Command = VoidTag("command")
+
+// Deprecated: <keygen> is obsolete.
+var Keygen = VoidTag("keygen")
+
+// Deprecated: <param> is obsolete.
+var Param = VoidTag("param")
+
+// Deprecated: <portal> is obsolete.
+var Portal = Tag("portal")
+
+// HTML Attributes
+// https://html.spec.whatwg.org/multipage/indices.html#attributes-3
+
+// Global attribute
+var Accesskey = Attrib("accesskey")
+
+// Global attribute
+var Autocapitalize = EnumeratedAttrib("autocapitalize", "off", "none", "on", "sentences", "words", "characters")
+
+// Global attribute
+var Autofocus = BooleanAttrib("autofocus")
+
+// Global attribute
+var Class = Attrib("class")
+
+// Global attribute
+var Contenteditable = EnumeratedAttrib("contenteditable", "true", "false", "plaintext-only")
+
+// Global attribute
+var DirAttr = EnumeratedAttrib("dir", "ltr", "rtl", "auto")
+
+// Global attribute
+var Draggable = EnumeratedAttrib("draggable", "true", "false")
+
+// Global attribute
+var Enterkeyhint = EnumeratedAttrib("enterkeyhint", "enter", "done", "go", "next", "previous", "search", "send")
+
+// Global attribute
+var Hidden = BooleanAttrib("hidden")
+
+// Global attribute
+var Id = Attrib("id")
+
+// Global attribute
+var Inert = BooleanAttrib("inert")
+
+// Global attribute
+var Inputmode = EnumeratedAttrib("inputmode", "none", "text", "tel", "email", "url", "numeric", "decimal", "search")
+
+// Global attribute
+var Is = Attrib("is")
+
+// Global attribute
+var Itemid = Attrib("itemid")
+
+// Global attribute
+var Itemprop = Attrib("itemprop")
+
+// Global attribute
+var Itemref = Attrib("itemref")
+
+// Global attribute
+var Itemscope = BooleanAttrib("itemscope")
+
+// Global attribute
+var Itemtype = Attrib("itemtype")
+
+// Global attribute
+var Lang = Attrib("lang")
+
+// Global attribute
+var Nonce = Attrib("nonce")
+
+// Global attribute
+var Popover = EnumeratedAttrib("popover", "auto", "manual")
+
+// Global attribute
+var Role = Attrib("role")
+
+// Global attribute
+var SlotAttr = Attrib("slot")
+
+// Global attribute
+var Spellcheck = EnumeratedAttrib("spellcheck", "true", "false")
+
+// Global attribute
+var Style = Attrib("style")
+
+// Global attribute
+var Tabindex = Attrib("tabindex")
+
+// Global attribute
+var TitleAttr = Attrib("title")
+
+// Global attribute
+var Translate = EnumeratedAttrib("translate", "yes", "no")
+
+// Attribute of <th>
+var AbbrAttr = Attrib("abbr")
+
+// Attribute of <input>
+var Accept = Attrib("accept")
+
+// Attribute of <form>
+var AcceptCharset = Attrib("accept-charset")
+
+// Attribute of <form>
+var Action = Attrib("action")
+
+// Attribute of <iframe>
+var Allow = Attrib("allow")
+
+// Attribute of <iframe>
+var Allowfullscreen = BooleanAttrib("allowfullscreen")
+
+// Attribute of <area>, <img>, <input>
+var Alt = Attrib("alt")
+
+// Attribute of <link>
+var As = Attrib("as")
+
+// Attribute of <script>
+var AsyncAttr = BooleanAttrib("async")
+
+// Attribute of <form>, <input>, <select>, <textarea>
+var Autocomplete = Attrib("autocomplete")
+
+// Attribute of <audio>, <video>
+var Autoplay = BooleanAttrib("autoplay")
+
+// Attribute of <link>, <script>, <style>
+var Blocking = Attrib("blocking")
+
+// Attribute of <meta>
+var Charset = Attrib("charset")
+
+// Attribute of <input>
+var CheckedAttr = BooleanAttrib("checked")
+
+// Attribute of <blockquote>, <del>, <ins>, <q>
+var CiteAttr = Attrib("cite")
+
+// Attribute of <textarea>
+var Cols = Attrib("cols")
+
+// Attribute of <td>, <th>
+var Colspan = Attrib("colspan")
+
+// Attribute of <meta>
+var Content = Attrib("content")
+
+// Attribute of <audio>, <video>
+var Controls = BooleanAttrib("controls")
+
+// Attribute of <area>
+var Coords = Attrib("coords")
+
+// Attribute of <audio>, <img>, <link>, <script>, <video>
+var Crossorigin = EnumeratedAttrib("crossorigin", "anonymous", "use-credentials")
+
+// Attribute of <object>
+var DataAttr = Attrib("data")
+
+// Attribute of <del>, <ins>, <time>
+var Datetime = Attrib("datetime")
+
+// Attribute of <img>
+var Decoding = EnumeratedAttrib("decoding", "sync", "async", "auto")
+
+// Attribute of <track>
+var DefaultAttr = BooleanAttrib("default")
+
+// Attribute of <script>
+var Defer = BooleanAttrib("defer")
+
+// Attribute of <input>, <textarea>
+var Dirname = Attrib("dirname")
+
+// Attribute of <button>, <fieldset>, <input>, <link>, <optgroup>, <option>, <select>, <textarea>
+var DisabledAttr = BooleanAttrib("disabled")
+
+// Attribute of <a>, <area>
+var Download = BooleanAttrib("download")
+
+// Attribute of <form>
+var Enctype = EnumeratedAttrib("enctype", "application/x-www-form-urlencoded", "multipart/form-data", "text/plain")
+
+// Attribute of <img>, <link>, <script>
+var Fetchpriority = EnumeratedAttrib("fetchpriority", "high", "low", "auto")
+
+// Attribute of <label>, <output>
+var For = Attrib("for")
+
+// Attribute of <button>, <fieldset>, <input>, <object>, <output>, <select>, <textarea>
+var FormAttr = Attrib("form")
+
+// Attribute of <button>, <input>
+var Formaction = Attrib("formaction")
+
+// Attribute of <button>, <input>
+var Formenctype = EnumeratedAttrib("formenctype", "application/x-www-form-urlencoded", "multipart/form-data", "text/plain")
+
+// Attribute of <button>, <input>
+var Formmethod = EnumeratedAttrib("formmethod", "get", "post", "dialog")
+
+// Attribute of <button>, <input>
+var Formnovalidate = BooleanAttrib("formnovalidate")
+
+// Attribute of <button>, <input>
+var Formtarget = Attrib("formtarget")
+
+// Attribute of <td>, <th>
+var Headers = Attrib("headers")
+
+// Attribute of <canvas>, <embed>, <iframe>, <img>, <input>, <object>, <source>, <video>
+var HeightAttr = Attrib("height")
+
+// Attribute of <meter>
+var High = Attrib("high")
+
+// Attribute of <a>, <area>, <base>, <link>
+var Href = Attrib("href")
+
+// Attribute of <a>, <link>
+var Hreflang = Attrib("hreflang")
+
+// Attribute of <meta>
+var HttpEquiv = Attrib("http-equiv")
+
+// Attribute of <link>
+var Imagesizes = Attrib("imagesizes")
+
+// Attribute of <link>
+var Imagesrcset = Attrib("imagesrcset")
+
+// Attribute of <link>, <script>
+var Integrity = Attrib("integrity")
+
+// Attribute of <img>
+var Ismap = BooleanAttrib("ismap")
+
+// Attribute of <track>
+var Kind = EnumeratedAttrib("kind", "subtitles", "captions", "descriptions", "chapters", "metadata")
+
+// Attribute of <optgroup>, <option>, <track>
+var LabelAttr = Attrib("label")
+
+// Attribute of <input>
+var List = Attrib("list")
+
+// Attribute of <iframe>, <img>
+var Loading = EnumeratedAttrib("loading", "lazy", "eager")
+
+// Attribute of <audio>, <video>
+var Loop = BooleanAttrib("loop")
+
+// Attribute of <meter>
+var Low = Attrib("low")
+
+// Attribute of <input>, <meter>, <progress>
+var Max = Attrib("max")
+
+// Attribute of <input>, <textarea>
+var Maxlength = Attrib("maxlength")
+
+// Attribute of <link>, <meta>, <source>, <style>
+var Media = Attrib("media")
+
+// Attribute of <form>
+var Method = EnumeratedAttrib("method", "get", "post", "dialog")
+
+// Attribute of <input>, <meter>
+var Min = Attrib("min")
+
+// Attribute of <input>, <textarea>
+var Minlength = Attrib("minlength")
+
+// Attribute of <input>, <select>
+var Multiple = BooleanAttrib("multiple")
+
+// Attribute of <audio>, <video>
+var Muted = BooleanAttrib("muted")
+
+// Attribute of <button>, <fieldset>, <form>, <iframe>, <input>, <map>, <meta>, <object>, <output>, <select>, <slot>, <textarea>
+var Name = Attrib("name")
+
+// Attribute of <script>
+var Nomodule = BooleanAttrib("nomodule")
+
+// Attribute of <form>
+var Novalidate = BooleanAttrib("novalidate")
+
+// Attribute of <details>, <dialog>
+var Open = BooleanAttrib("open")
+
+// Attribute of <meter>
+var Optimum = Attrib("optimum")
+
+// Attribute of <input>
+var Pattern = Attrib("pattern")
+
+// Attribute of <a>, <area>
+var Ping = Attrib("ping")
+
+// Attribute of <input>, <textarea>
+var Placeholder = Attrib("placeholder")
+
+// Attribute of <video>
+var Playsinline = BooleanAttrib("playsinline")
+
+// Attribute of <button>, <input>
+var Popovertarget = Attrib("popovertarget")
+
+// Attribute of <button>, <input>
+var Popovertargetaction = EnumeratedAttrib("popovertargetaction", "toggle", "show", "hide")
+
+// Attribute of <video>
+var Poster = Attrib("poster")
+
+// Attribute of <audio>, <video>
+var Preload = EnumeratedAttrib("preload", "none", "metadata", "auto")
+
+// Attribute of <input>, <textarea>
+var Readonly = BooleanAttrib("readonly")
+
+// Attribute of <a>, <area>, <iframe>, <img>, <link>, <script>
+var Referrerpolicy = EnumeratedAttrib("referrerpolicy", "", "no-referrer", "no-referrer-when-downgrade", "same-origin", "origin", "strict-origin", "origin-when-cross-origin", "strict-origin-when-cross-origin", "unsafe-url")
+
+// Attribute of <a>, <area>, <form>, <link>
+var Rel = Attrib("rel")
+
+// Attribute of <input>, <select>, <textarea>
+var Required = BooleanAttrib("required")
+
+// Attribute of <ol>
+var Reversed = BooleanAttrib("reversed")
+
+// Attribute of <textarea>
+var Rows = Attrib("rows")
+
+// Attribute of <td>, <th>
+var Rowspan = Attrib("rowspan")
+
+// Attribute of <iframe>
+var Sandbox = Attrib("sandbox")
+
+// Attribute of <th>
+var Scope = EnumeratedAttrib("scope", "row", "col", "rowgroup", "colgroup")
+
+// Attribute of <option>
+var Selected = BooleanAttrib("selected")
+
+// Attribute of <template>
+var Shadowrootmode = EnumeratedAttrib("shadowrootmode", "open", "closed")
+
+// Attribute of <area>
+var Shape = EnumeratedAttrib("shape", "circle", "default", "poly", "rect")
+
+// Attribute of <input>, <select>
+var SizeAttr = Attrib("size")
+
+// Attribute of <img>, <link>, <source>
+var Sizes = Attrib("sizes")
+
+// Attribute of <col>, <colgroup>
+var SpanAttr = Attrib("span")
+
+// Attribute of <audio>, <embed>, <iframe>, <img>, <input>, <script>, <source>, <track>, <video>
+var Src = Attrib("src")
+
+// Attribute of <iframe>
+var Srcdoc = Attrib("srcdoc")
+
+// Attribute of <track>
+var Srclang = Attrib("srclang")
+
+// Attribute of <img>, <source>
+var Srcset = Attrib("srcset")
+
+// Attribute of <ol>
+var Start = Attrib("start")
+
+// Attribute of <input>
+var Step = Attrib("step")
+
+// Attribute of <a>, <area>, <base>, <form>
+var Target = Attrib("target")
+
+// Attribute of <a>, <button>, <embed>, <input>, <link>, <object>, <ol>, <script>, <source>, <style>
+var Type = Attrib("type")
+
+// Attribute of <img>
+var Usemap = Attrib("usemap")
+
+// Attribute of <button>, <data>, <input>, <li>, <meter>, <option>, <output>, <param>, <progress>
+var Value = Attrib("value")
+
+// Attribute of <canvas>, <embed>, <iframe>, <img>, <input>, <object>, <source>, <video>
+var WidthAttr = Attrib("width")
+
+// Attribute of <textarea>
+var Wrap = EnumeratedAttrib("wrap", "soft", "hard")
+
+// All tags by their HTML name
+var TagCatalog = map[string]TagInfo{
+	"a":          {GoName: "A", Void: false},
+	"abbr":       {GoName: "Abbr", Void: false},
+	"address":    {GoName: "Address", Void: false},
+	"area":       {GoName: "Area", Void: true},
+	"article":    {GoName: "Article", Void: false},
+	"aside":      {GoName: "Aside", Void: false},
+	"audio":      {GoName: "Audio", Void: false},
+	"b":          {GoName: "B", Void: false},
+	"base":       {GoName: "Base", Void: true},
+	"bdi":        {GoName: "Bdi", Void: false},
+	"bdo":        {GoName: "Bdo", Void: false},
+	"blockquote": {GoName: "Blockquote", Void: false},
+	"body":       {GoName: "Body", Void: false},
+	"br":         {GoName: "Br", Void: true},
+	"button":     {GoName: "Button", Void: false},
+	"canvas":     {GoName: "Canvas", Void: false},
+	"caption":    {GoName: "Caption", Void: false},
+	"cite":       {GoName: "Cite", Void: false},
+	"code":       {GoName: "Code", Void: false},
+	"col":        {GoName: "Col", Void: true},
+	"colgroup":   {GoName: "Colgroup", Void: false},
+	"command":    {GoName: "Command", Void: true},
+	"data":       {GoName: "Data", Void: false},
+	"datalist":   {GoName: "Datalist", Void: false},
+	"dd":         {GoName: "Dd", Void: false},
+	"del":        {GoName: "Del", Void: false},
+	"details":    {GoName: "Details", Void: false},
+	"dfn":        {GoName: "Dfn", Void: false},
+	"dialog":     {GoName: "Dialog", Void: false},
+	"div":        {GoName: "Div", Void: false},
+	"dl":         {GoName: "Dl", Void: false},
+	"dt":         {GoName: "Dt", Void: false},
+	"em":         {GoName: "EmTag", Void: false},
+	"embed":      {GoName: "Embed", Void: true},
+	"fieldset":   {GoName: "Fieldset", Void: false},
+	"figcaption": {GoName: "Figcaption", Void: false},
+	"figure":     {GoName: "Figure", Void: false},
+	"footer":     {GoName: "Footer", Void: false},
+	"form":       {GoName: "Form", Void: false},
+	"h1":         {GoName: "H1", Void: false},
+	"h2":         {GoName: "H2", Void: false},
+	"h3":         {GoName: "H3", Void: false},
+	"h4":         {GoName: "H4", Void: false},
+	"h5":         {GoName: "H5", Void: false},
+	"h6":         {GoName: "H6", Void: false},
+	"head":       {GoName: "Head", Void: false},
+	"header":     {GoName: "Header", Void: false},
+	"hgroup":     {GoName: "Hgroup", Void: false},
+	"hr":         {GoName: "Hr", Void: true},
+	"html":       {GoName: "Html", Void: false},
+	"i":          {GoName: "I", Void: false},
+	"iframe":     {GoName: "Iframe", Void: false},
+	"img":        {GoName: "Img", Void: true},
+	"input":      {GoName: "Input", Void: true},
+	"ins":        {GoName: "Ins", Void: false},
+	"kbd":        {GoName: "Kbd", Void: false},
+	"keygen":     {GoName: "Keygen", Void: true},
+	"label":      {GoName: "Label", Void: false},
+	"legend":     {GoName: "Legend", Void: false},
+	"li":         {GoName: "Li", Void: false},
+	"link":       {GoName: "Link", Void: true},
+	"main":       {GoName: "Main", Void: false},
+	"map":        {GoName: "Map", Void: false},
+	"mark":       {GoName: "Mark", Void: false},
+	"menu":       {GoName: "Menu", Void: false},
+	"meta":       {GoName: "Meta", Void: true},
+	"meter":      {GoName: "Meter", Void: false},
+	"nav":        {GoName: "Nav", Void: false},
+	"noscript":   {GoName: "Noscript", Void: false},
+	"object":     {GoName: "Object", Void: false},
+	"ol":         {GoName: "Ol", Void: false},
+	"optgroup":   {GoName: "Optgroup", Void: false},
+	"option":     {GoName: "Option", Void: false},
+	"output":     {GoName: "Output", Void: false},
+	"p":          {GoName: "P", Void: false},
+	"param":      {GoName: "Param", Void: true},
+	"picture":    {GoName: "Picture", Void: false},
+	"portal":     {GoName: "Portal", Void: false},
+	"pre":        {GoName: "Pre", Void: false},
+	"progress":   {GoName: "Progress", Void: false},
+	"q":          {GoName: "Q", Void: false},
+	"rp":         {GoName: "Rp", Void: false},
+	"rt":         {GoName: "Rt", Void: false},
+	"ruby":       {GoName: "Ruby", Void: false},
+	"s":          {GoName: "S", Void: false},
+	"samp":       {GoName: "Samp", Void: false},
+	"script":     {GoName: "Script", Void: false},
+	"search":     {GoName: "Search", Void: false},
+	"section":    {GoName: "Section", Void: false},
+	"select":     {GoName: "Select", Void: false},
+	"slot":       {GoName: "Slot", Void: false},
+	"small":      {GoName: "Small", Void: false},
+	"source":     {GoName: "Source", Void: true},
+	"span":       {GoName: "Span", Void: false},
+	"strong":     {GoName: "Strong", Void: false},
+	"style":      {GoName: "StyleTag", Void: false},
+	"sub":        {GoName: "SubTag", Void: false},
+	"summary":    {GoName: "Summary", Void: false},
+	"sup":        {GoName: "Sup", Void: false},
+	"table":      {GoName: "Table", Void: false},
+	"tbody":      {GoName: "Tbody", Void: false},
+	"td":         {GoName: "Td", Void: false},
+	"template":   {GoName: "Template", Void: false},
+	"textarea":   {GoName: "Textarea", Void: false},
+	"tfoot":      {GoName: "Tfoot", Void: false},
+	"th":         {GoName: "Th", Void: false},
+	"thead":      {GoName: "Thead", Void: false},
+	"time":       {GoName: "Time", Void: false},
+	"title":      {GoName: "Title", Void: false},
+	"tr":         {GoName: "Tr", Void: false},
+	"track":      {GoName: "Track", Void: true},
+	"u":          {GoName: "U", Void: false},
+	"ul":         {GoName: "Ul", Void: false},
+	"var":        {GoName: "VarTag", Void: false},
+	"video":      {GoName: "Video", Void: false},
+	"wbr":        {GoName: "Wbr", Void: true},
+}
+
+// All attributes by their HTML name
+var AttributeCatalog = map[string]AttributeInfo{
+	"abbr":                {GoName: "AbbrAttr", Elements: []string{"th"}},
+	"accept":              {GoName: "Accept", Elements: []string{"input"}},
+	"accept-charset":      {GoName: "AcceptCharset", Elements: []string{"form"}},
+	"accesskey":           {GoName: "Accesskey"},
+	"action":              {GoName: "Action", Elements: []string{"form"}},
+	"allow":               {GoName: "Allow", Elements: []string{"iframe"}},
+	"allowfullscreen":     {GoName: "Allowfullscreen", Boolean: true, Elements: []string{"iframe"}},
+	"alt":                 {GoName: "Alt", Elements: []string{"area", "img", "input"}},
+	"as":                  {GoName: "As", Elements: []string{"link"}},
+	"async":               {GoName: "AsyncAttr", Boolean: true, Elements: []string{"script"}},
+	"autocapitalize":      {GoName: "Autocapitalize", Values: []string{"off", "none", "on", "sentences", "words", "characters"}},
+	"autocomplete":        {GoName: "Autocomplete", Elements: []string{"form", "input", "select", "textarea"}},
+	"autofocus":           {GoName: "Autofocus", Boolean: true},
+	"autoplay":            {GoName: "Autoplay", Boolean: true, Elements: []string{"audio", "video"}},
+	"blocking":            {GoName: "Blocking", Elements: []string{"link", "script", "style"}},
+	"charset":             {GoName: "Charset", Elements: []string{"meta"}},
+	"checked":             {GoName: "CheckedAttr", Boolean: true, Elements: []string{"input"}},
+	"cite":                {GoName: "CiteAttr", Elements: []string{"blockquote", "del", "ins", "q"}},
+	"class":               {GoName: "Class"},
+	"cols":                {GoName: "Cols", Elements: []string{"textarea"}},
+	"colspan":             {GoName: "Colspan", Elements: []string{"td", "th"}},
+	"content":             {GoName: "Content", Elements: []string{"meta"}},
+	"contenteditable":     {GoName: "Contenteditable", Values: []string{"true", "false", "plaintext-only"}},
+	"controls":            {GoName: "Controls", Boolean: true, Elements: []string{"audio", "video"}},
+	"coords":              {GoName: "Coords", Elements: []string{"area"}},
+	"crossorigin":         {GoName: "Crossorigin", Values: []string{"anonymous", "use-credentials"}, Elements: []string{"audio", "img", "link", "script", "video"}},
+	"data":                {GoName: "DataAttr", Elements: []string{"object"}},
+	"datetime":            {GoName: "Datetime", Elements: []string{"del", "ins", "time"}},
+	"decoding":            {GoName: "Decoding", Values: []string{"sync", "async", "auto"}, Elements: []string{"img"}},
+	"default":             {GoName: "DefaultAttr", Boolean: true, Elements: []string{"track"}},
+	"defer":               {GoName: "Defer", Boolean: true, Elements: []string{"script"}},
+	"dir":                 {GoName: "DirAttr", Values: []string{"ltr", "rtl", "auto"}},
+	"dirname":             {GoName: "Dirname", Elements: []string{"input", "textarea"}},
+	"disabled":            {GoName: "DisabledAttr", Boolean: true, Elements: []string{"button", "fieldset", "input", "link", "optgroup", "option", "select", "textarea"}},
+	"download":            {GoName: "Download", Boolean: true, Elements: []string{"a", "area"}},
+	"draggable":           {GoName: "Draggable", Values: []string{"true", "false"}},
+	"enctype":             {GoName: "Enctype", Values: []string{"application/x-www-form-urlencoded", "multipart/form-data", "text/plain"}, Elements: []string{"form"}},
+	"enterkeyhint":        {GoName: "Enterkeyhint", Values: []string{"enter", "done", "go", "next", "previous", "search", "send"}},
+	"fetchpriority":       {GoName: "Fetchpriority", Values: []string{"high", "low", "auto"}, Elements: []string{"img", "link", "script"}},
+	"for":                 {GoName: "For", Elements: []string{"label", "output"}},
+	"form":                {GoName: "FormAttr", Elements: []string{"button", "fieldset", "input", "object", "output", "select", "textarea"}},
+	"formaction":          {GoName: "Formaction", Elements: []string{"button", "input"}},
+	"formenctype":         {GoName: "Formenctype", Values: []string{"application/x-www-form-urlencoded", "multipart/form-data", "text/plain"}, Elements: []string{"button", "input"}},
+	"formmethod":          {GoName: "Formmethod", Values: []string{"get", "post", "dialog"}, Elements: []string{"button", "input"}},
+	"formnovalidate":      {GoName: "Formnovalidate", Boolean: true, Elements: []string{"button", "input"}},
+	"formtarget":          {GoName: "Formtarget", Elements: []string{"button", "input"}},
+	"headers":             {GoName: "Headers", Elements: []string{"td", "th"}},
+	"height":              {GoName: "HeightAttr", Elements: []string{"canvas", "embed", "iframe", "img", "input", "object", "source", "video"}},
+	"hidden":              {GoName: "Hidden", Boolean: true},
+	"high":                {GoName: "High", Elements: []string{"meter"}},
+	"href":                {GoName: "Href", Elements: []string{"a", "area", "base", "link"}},
+	"hreflang":            {GoName: "Hreflang", Elements: []string{"a", "link"}},
+	"http-equiv":          {GoName: "HttpEquiv", Elements: []string{"meta"}},
+	"id":                  {GoName: "Id"},
+	"imagesizes":          {GoName: "Imagesizes", Elements: []string{"link"}},
+	"imagesrcset":         {GoName: "Imagesrcset", Elements: []string{"link"}},
+	"inert":               {GoName: "Inert", Boolean: true},
+	"inputmode":           {GoName: "Inputmode", Values: []string{"none", "text", "tel", "email", "url", "numeric", "decimal", "search"}},
+	"integrity":           {GoName: "Integrity", Elements: []string{"link", "script"}},
+	"is":                  {GoName: "Is"},
+	"ismap":               {GoName: "Ismap", Boolean: true, Elements: []string{"img"}},
+	"itemid":              {GoName: "Itemid"},
+	"itemprop":            {GoName: "Itemprop"},
+	"itemref":             {GoName: "Itemref"},
+	"itemscope":           {GoName: "Itemscope", Boolean: true},
+	"itemtype":            {GoName: "Itemtype"},
+	"kind":                {GoName: "Kind", Values: []string{"subtitles", "captions", "descriptions", "chapters", "metadata"}, Elements: []string{"track"}},
+	"label":               {GoName: "LabelAttr", Elements: []string{"optgroup", "option", "track"}},
+	"lang":                {GoName: "Lang"},
+	"list":                {GoName: "List", Elements: []string{"input"}},
+	"loading":             {GoName: "Loading", Values: []string{"lazy", "eager"}, Elements: []string{"iframe", "img"}},
+	"loop":                {GoName: "Loop", Boolean: true, Elements: []string{"audio", "video"}},
+	"low":                 {GoName: "Low", Elements: []string{"meter"}},
+	"max":                 {GoName: "Max", Elements: []string{"input", "meter", "progress"}},
+	"maxlength":           {GoName: "Maxlength", Elements: []string{"input", "textarea"}},
+	"media":               {GoName: "Media", Elements: []string{"link", "meta", "source", "style"}},
+	"method":              {GoName: "Method", Values: []string{"get", "post", "dialog"}, Elements: []string{"form"}},
+	"min":                 {GoName: "Min", Elements: []string{"input", "meter"}},
+	"minlength":           {GoName: "Minlength", Elements: []string{"input", "textarea"}},
+	"multiple":            {GoName: "Multiple", Boolean: true, Elements: []string{"input", "select"}},
+	"muted":               {GoName: "Muted", Boolean: true, Elements: []string{"audio", "video"}},
+	"name":                {GoName: "Name", Elements: []string{"button", "fieldset", "form", "iframe", "input", "map", "meta", "object", "output", "select", "slot", "textarea"}},
+	"nomodule":            {GoName: "Nomodule", Boolean: true, Elements: []string{"script"}},
+	"nonce":               {GoName: "Nonce"},
+	"novalidate":          {GoName: "Novalidate", Boolean: true, Elements: []string{"form"}},
+	"open":                {GoName: "Open", Boolean: true, Elements: []string{"details", "dialog"}},
+	"optimum":             {GoName: "Optimum", Elements: []string{"meter"}},
+	"pattern":             {GoName: "Pattern", Elements: []string{"input"}},
+	"ping":                {GoName: "Ping", Elements: []string{"a", "area"}},
+	"placeholder":         {GoName: "Placeholder", Elements: []string{"input", "textarea"}},
+	"playsinline":         {GoName: "Playsinline", Boolean: true, Elements: []string{"video"}},
+	"popover":             {GoName: "Popover", Values: []string{"auto", "manual"}},
+	"popovertarget":       {GoName: "Popovertarget", Elements: []string{"button", "input"}},
+	"popovertargetaction": {GoName: "Popovertargetaction", Values: []string{"toggle", "show", "hide"}, Elements: []string{"button", "input"}},
+	"poster":              {GoName: "Poster", Elements: []string{"video"}},
+	"preload":             {GoName: "Preload", Values: []string{"none", "metadata", "auto"}, Elements: []string{"audio", "video"}},
+	"readonly":            {GoName: "Readonly", Boolean: true, Elements: []string{"input", "textarea"}},
+	"referrerpolicy":      {GoName: "Referrerpolicy", Values: []string{"", "no-referrer", "no-referrer-when-downgrade", "same-origin", "origin", "strict-origin", "origin-when-cross-origin", "strict-origin-when-cross-origin", "unsafe-url"}, Elements: []string{"a", "area", "iframe", "img", "link", "script"}},
+	"rel":                 {GoName: "Rel", Elements: []string{"a", "area", "form", "link"}},
+	"required":            {GoName: "Required", Boolean: true, Elements: []string{"input", "select", "textarea"}},
+	"reversed":            {GoName: "Reversed", Boolean: true, Elements: []string{"ol"}},
+	"role":                {GoName: "Role"},
+	"rows":                {GoName: "Rows", Elements: []string{"textarea"}},
+	"rowspan":             {GoName: "Rowspan", Elements: []string{"td", "th"}},
+	"sandbox":             {GoName: "Sandbox", Elements: []string{"iframe"}},
+	"scope":               {GoName: "Scope", Values: []string{"row", "col", "rowgroup", "colgroup"}, Elements: []string{"th"}},
+	"selected":            {GoName: "Selected", Boolean: true, Elements: []string{"option"}},
+	"shadowrootmode":      {GoName: "Shadowrootmode", Values: []string{"open", "closed"}, Elements: []string{"template"}},
+	"shape":               {GoName: "Shape", Values: []string{"circle", "default", "poly", "rect"}, Elements: []string{"area"}},
+	"size":                {GoName: "SizeAttr", Elements: []string{"input", "select"}},
+	"sizes":               {GoName: "Sizes", Elements: []string{"img", "link", "source"}},
+	"slot":                {GoName: "SlotAttr"},
+	"span":                {GoName: "SpanAttr", Elements: []string{"col", "colgroup"}},
+	"spellcheck":          {GoName: "Spellcheck", Values: []string{"true", "false"}},
+	"src":                 {GoName: "Src", Elements: []string{"audio", "embed", "iframe", "img", "input", "script", "source", "track", "video"}},
+	"srcdoc":              {GoName: "Srcdoc", Elements: []string{"iframe"}},
+	"srclang":             {GoName: "Srclang", Elements: []string{"track"}},
+	"srcset":              {GoName: "Srcset", Elements: []string{"img", "source"}},
+	"start":               {GoName: "Start", Elements: []string{"ol"}},
+	"step":                {GoName: "Step", Elements: []string{"input"}},
+	"style":               {GoName: "Style"},
+	"tabindex":            {GoName: "Tabindex"},
+	"target":              {GoName: "Target", Elements: []string{"a", "area", "base", "form"}},
+	"title":               {GoName: "TitleAttr"},
+	"translate":           {GoName: "Translate", Values: []string{"yes", "no"}},
+	"type":                {GoName: "Type", Elements: []string{"a", "button", "embed", "input", "link", "object", "ol", "script", "source", "style"}},
+	"usemap":              {GoName: "Usemap", Elements: []string{"img"}},
+	"value":               {GoName: "Value", Elements: []string{"button", "data", "input", "li", "meter", "option", "output", "param", "progress"}},
+	"width":               {GoName: "WidthAttr", Elements: []string{"canvas", "embed", "iframe", "img", "input", "object", "source", "video"}},
+	"wrap":                {GoName: "Wrap", Values: []string{"soft", "hard"}, Elements: []string{"textarea"}},
+}
+
+// CSS properties with a declaration function (see css.go) by their name
+var CSSPropertyCatalog = map[string]string{
+	"align-items":           "AlignItems",
+	"background":            "Background",
+	"background-color":      "BackgroundColor",
+	"background-image":      "BackgroundImage",
+	"background-position":   "BackgroundPosition",
+	"background-size":       "BackgroundSize",
+	"border":                "Border",
+	"border-bottom":         "BorderBottom",
+	"border-color":          "BorderColor",
+	"border-left":           "BorderLeft",
+	"border-left-width":     "BorderLeftWidth",
+	"border-radius":         "BorderRadius",
+	"border-right":          "BorderRight",
+	"border-right-width":    "BorderRightWidth",
+	"border-style":          "BorderStyle",
+	"border-top":            "BorderTop",
+	"border-width":          "BorderWidth",
+	"bottom":                "Bottom",
+	"box-shadow":            "BoxShadow",
+	"box-sizing":            "BoxSizing",
+	"color":                 "Color",
+	"display":               "Display",
+	"fill":                  "Fill",
+	"filter":                "Filter",
+	"flex-basis":            "FlexBasis",
+	"flex-direction":        "FlexDirection",
+	"flex-grow":             "FlexGrow",
+	"flex-shrink":           "FlexShrink",
+	"font-family":           "FontFamily",
+	"font-size":             "FontSize",
+	"font-stretch":          "FontStretch",
+	"font-weight":           "FontWeight",
+	"grid-auto-rows":        "GridAutoRows",
+	"grid-gap":              "GridGap",
+	"grid-template-columns": "GridTemplateColumns",
+	"height":                "Height",
+	"justify-content":       "JustifyContent",
+	"justify-items":         "JustifyItems",
+	"left":                  "Left",
+	"letter-spacing":        "LetterSpacing",
+	"line-height":           "LineHeight",
+	"list-style":            "ListStyle",
+	"margin":                "Margin",
+	"margin-bottom":         "MarginBottom",
+	"margin-left":           "MarginLeft",
+	"margin-right":          "MarginRight",
+	"margin-top":            "MarginTop",
+	"max-height":            "MaxHeight",
+	"max-width":             "MaxWidth",
+	"min-height":            "MinHeight",
+	"min-width":             "MinWidth",
+	"opacity":               "Opacity",
+	"padding":               "Padding",
+	"padding-bottom":        "PaddingBottom",
+	"padding-left":          "PaddingLeft",
+	"padding-right":         "PaddingRight",
+	"padding-top":           "PaddingTop",
+	"position":              "Position",
+	"stroke":                "Stroke",
+	"stroke-width":          "StrokeWidth",
+	"text-align":            "TextAlign",
+	"text-decoration":       "TextDecoration",
+	"text-transform":        "TextTransform",
+	"top":                   "Top",
+	"transform":             "Transform",
+	"transform-origin":      "TransformOrigin",
+	"transition":            "Transition",
+	"width":                 "Width",
+}
@@ -0,0 +1,106 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package gospel
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestEventHandlers(t *testing.T) {
+
+	clicks := 0
+	var changed string
+
+	root := func(c Context) Element {
+		name := Var(c, "")
+		return F(
+			Button(OnClick(Func[any](c, func() { clicks++ })), "+"),
+			Input(Value(name), OnChange(Func[any](c, func() { changed = name.Get() }))),
+			// client-side handlers are only kept as they are if they're safe
+			Span(OnClick(SafeJS("void(0)"))),
+			Strong(OnClick("void(0)")),
+		)
+	}
+
+	request := func(method string, form url.Values) (*Router, string) {
+		r := httptest.NewRequest(method, "/items?page=2", strings.NewReader(form.Encode()))
+		r.Header.Set("content-type", "application/x-www-form-urlencoded")
+		c := MakeDefaultContext(r, httptest.NewRecorder(), MakeStore(MakeCookieStore("")))
+		router := MakeRouter(c)
+		return router, c.Execute(root).RenderElement()
+	}
+
+	_, html := request("GET", nil)
+
+	for _, marker := range []string{`gospel-onClick="root.1"`, `gospel-onChange="root.2"`, `<span onClick="void(0)">`, `<strong onClick="&#34;void(0)&#34;">`} {
+		if !strings.Contains(html, marker) {
+			t.Fatalf("expected %s in %s", marker, html)
+		}
+	}
+
+	// a click event only calls the matching function
+	router, _ := request("POST", url.Values{"_gspl": {"root.1"}, eventField: {"click"}})
+
+	if clicks != 1 || changed != "" {
+		t.Fatalf("expected one click: %d, '%s'", clicks, changed)
+	}
+
+	if router.RedirectedTo() != "/items?page=2" {
+		t.Fatalf("expected a redirect to the current page, got '%s'", router.RedirectedTo())
+	}
+
+	// a change event assigns the new value before calling the function
+	request("POST", url.Values{"_gspl": {"root.2"}, eventField: {"change"}, "0": {"foo"}})
+
+	if clicks != 1 || changed != "foo" {
+		t.Fatalf("expected a change: %d, '%s'", clicks, changed)
+	}
+
+	// the event type has to match as well
+	request("POST", url.Values{"_gspl": {"root.1"}, eventField: {"change"}})
+
+	if clicks != 1 {
+		t.Fatalf("expected no additional click: %d", clicks)
+	}
+}
+
+func TestCatalog(t *testing.T) {
+
+	for name, info := range TagCatalog {
+
+		tag, ok := elements[name]
+
+		if !ok {
+			t.Fatalf("tag '%s' isn't registered", name)
+		}
+
+		if element := tag(); element.Void != info.Void {
+			t.Fatalf("tag '%s' should be void: %v", name, info.Void)
+		}
+	}
+
+	html := Input(Required(), Type("number"), Min(1), Max(10), Autocomplete("off"), FormAttr("search")).RenderElement()
+
+	if html != `<input required type="number" min="1" max="10" autocomplete="off" form="search"/>` {
+		t.Fatalf("unexpected output: %s", html)
+	}
+
+	if !containsFold(AttributeCatalog["method"].Values, "POST") {
+		t.Fatalf("expected enumerated values to be case-insensitive")
+	}
+}
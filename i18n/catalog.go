@@ -0,0 +1,490 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package i18n
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// The plural forms of a message, in the order given by the plural rule of
+// the locale. Messages without plural forms have a single form.
+type Message []string
+
+// Maps message keys to messages
+type Catalog map[string]Message
+
+// A collection of catalogs for different locales
+type Bundle struct {
+	mutex         sync.RWMutex
+	DefaultLocale string
+	catalogs      map[string]Catalog
+}
+
+func MakeBundle(defaultLocale string) *Bundle {
+	return &Bundle{
+		DefaultLocale: Normalize(defaultLocale),
+		catalogs:      make(map[string]Catalog),
+	}
+}
+
+// Normalizes a locale tag, e.g. "de_AT" becomes "de-at"
+func Normalize(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// Returns the language of a locale tag, e.g. "de" for "de-AT"
+func Language(locale string) string {
+	locale = Normalize(locale)
+	if i := strings.Index(locale, "-"); i != -1 {
+		return locale[:i]
+	}
+	return locale
+}
+
+func (b *Bundle) Add(locale, key string, forms ...string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	locale = Normalize(locale)
+	catalog, ok := b.catalogs[locale]
+
+	if !ok {
+		catalog = make(Catalog)
+		b.catalogs[locale] = catalog
+	}
+
+	catalog[key] = Message(forms)
+}
+
+// Returns the locales for which we have catalogs
+func (b *Bundle) Locales() []string {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	locales := make([]string, 0, len(b.catalogs))
+
+	for locale := range b.catalogs {
+		locales = append(locales, locale)
+	}
+
+	sort.Strings(locales)
+
+	return locales
+}
+
+// Checks whether we have a catalog for the exact locale
+func (b *Bundle) Supports(locale string) bool {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	_, ok := b.catalogs[Normalize(locale)]
+	return ok
+}
+
+// Returns the best supported locale for the given one, or an empty string
+func (b *Bundle) Match(locale string) string {
+
+	locale = Normalize(locale)
+
+	if locale == "" {
+		return ""
+	}
+
+	if b.Supports(locale) {
+		return locale
+	}
+
+	if language := Language(locale); b.Supports(language) {
+		return language
+	}
+
+	// we accept any regional variant of the language as well
+	for _, supported := range b.Locales() {
+		if Language(supported) == Language(locale) {
+			return supported
+		}
+	}
+
+	return ""
+}
+
+// Looks up a message, falling back to the language and the default locale
+func (b *Bundle) Message(locale, key string) (Message, string, bool) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	locale = Normalize(locale)
+
+	for _, candidate := range []string{locale, Language(locale), b.DefaultLocale} {
+		if catalog, ok := b.catalogs[candidate]; ok {
+			if message, ok := catalog[key]; ok && len(message) > 0 {
+				return message, candidate, true
+			}
+		}
+	}
+
+	return nil, "", false
+}
+
+// Translates a message. If the first argument is an integer, we use it to
+// select the plural form. Arguments are formatted using fmt.Sprintf, forms
+// that don't use all of them (e.g. "One item") only get the ones they use.
+func (b *Bundle) Translate(locale, key string, args ...any) string {
+
+	message, messageLocale, ok := b.Message(locale, key)
+
+	if !ok {
+		// we return the key so that missing translations are visible
+		if len(args) > 0 {
+			return fmt.Sprintf(key, args...)
+		}
+		return key
+	}
+
+	form := message[0]
+
+	if len(message) > 1 && len(args) > 0 {
+		if n, ok := count(args[0]); ok {
+			i := Plural(messageLocale).Index(n)
+			if i >= len(message) {
+				i = len(message) - 1
+			}
+			form = message[i]
+		}
+	}
+
+	// the count isn't necessarily part of the message (e.g. "One item")
+	if n := arguments(form); n < len(args) {
+		args = args[:n]
+	}
+
+	if len(args) > 0 {
+		return fmt.Sprintf(form, args...)
+	}
+
+	return form
+}
+
+// Returns the number of arguments a format string uses
+func arguments(format string) int {
+
+	next, used := 0, 0
+
+	for i := 0; i < len(format); i++ {
+
+		if format[i] != '%' {
+			continue
+		}
+
+		// we skip flags, width and precision, which can use arguments as well
+		for i++; i < len(format); i++ {
+			if c := format[i]; c == '*' {
+				next++
+			} else if c == '[' {
+				end := strings.IndexByte(format[i:], ']')
+				if end < 0 {
+					break
+				}
+				if index, err := strconv.Atoi(format[i+1 : i+end]); err == nil {
+					next = index - 1
+				}
+				i += end
+			} else if !strings.ContainsRune("+-# 0123456789.", rune(c)) {
+				break
+			}
+			if next > used {
+				used = next
+			}
+		}
+
+		if i < len(format) && format[i] != '%' {
+			next++
+		}
+
+		if next > used {
+			used = next
+		}
+	}
+
+	return used
+}
+
+func count(value any) (int64, bool) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint:
+		return int64(v), true
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint64:
+		return int64(v), true
+	}
+	return 0, false
+}
+
+// Loads all catalogs from a directory. Files have to be named after their
+// locale, e.g. "de.json" or "pt-BR.po".
+func (b *Bundle) LoadFS(fsys fs.FS, dir string) error {
+
+	entries, err := fs.ReadDir(fsys, dir)
+
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+
+		if entry.IsDir() {
+			continue
+		}
+
+		name := entry.Name()
+		ext := path.Ext(name)
+
+		if ext != ".json" && ext != ".po" {
+			continue
+		}
+
+		data, err := fs.ReadFile(fsys, path.Join(dir, name))
+
+		if err != nil {
+			return err
+		}
+
+		locale := strings.TrimSuffix(name, ext)
+
+		if ext == ".json" {
+			err = b.LoadJSON(locale, data)
+		} else {
+			err = b.LoadPO(locale, data)
+		}
+
+		if err != nil {
+			return fmt.Errorf("cannot load catalog '%s': %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// Loads a JSON catalog. Messages are either strings, lists of plural forms
+// or objects with CLDR plural categories ("one", "other", ...) as keys.
+// Other objects are treated as namespaces, e.g. {"nav": {"home": "Home"}}
+// defines the key "nav.home".
+func (b *Bundle) LoadJSON(locale string, data []byte) error {
+
+	var messages map[string]any
+
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return err
+	}
+
+	return b.loadJSONMessages(locale, "", messages)
+}
+
+func (b *Bundle) loadJSONMessages(locale, prefix string, messages map[string]any) error {
+
+	rule := Plural(locale)
+
+	for key, value := range messages {
+
+		fullKey := prefix + key
+
+		switch v := value.(type) {
+		case string:
+			b.Add(locale, fullKey, v)
+		case []any:
+			forms := make([]string, len(v))
+			for i, form := range v {
+				strForm, ok := form.(string)
+				if !ok {
+					return fmt.Errorf("invalid plural form for key '%s'", fullKey)
+				}
+				forms[i] = strForm
+			}
+			b.Add(locale, fullKey, forms...)
+		case map[string]any:
+
+			plural := len(v) > 0
+
+			for category, form := range v {
+				if _, ok := form.(string); !ok || !isCategory(category) {
+					plural = false
+					break
+				}
+			}
+
+			if !plural {
+				if err := b.loadJSONMessages(locale, fullKey+".", v); err != nil {
+					return err
+				}
+				continue
+			}
+
+			forms := make([]string, len(rule.Categories))
+
+			for i, category := range rule.Categories {
+				if form, ok := v[category]; ok {
+					forms[i] = form.(string)
+				} else if form, ok := v["other"]; ok {
+					forms[i] = form.(string)
+				}
+			}
+
+			b.Add(locale, fullKey, forms...)
+		default:
+			return fmt.Errorf("invalid message for key '%s'", fullKey)
+		}
+	}
+
+	return nil
+}
+
+// Loads a gettext PO catalog. We use the message ID as the key, and the
+// context (msgctxt) as a prefix separated by a dot if it's given.
+func (b *Bundle) LoadPO(locale string, data []byte) error {
+
+	var msgctxt, msgid string
+	var forms []string
+	var field *string
+	inEntry := false
+
+	flush := func() {
+		// the header entry has an empty ID
+		if inEntry && msgid != "" && len(forms) > 0 && forms[0] != "" {
+			key := msgid
+			if msgctxt != "" {
+				key = msgctxt + "." + msgid
+			}
+			b.Add(locale, key, forms...)
+		}
+		msgctxt, msgid, forms, field, inEntry = "", "", nil, nil, false
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNumber := 0
+
+	for scanner.Scan() {
+
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" {
+			flush()
+			continue
+		}
+
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "\"") {
+
+			if field == nil {
+				return fmt.Errorf("line %d: unexpected string", lineNumber)
+			}
+
+			value, err := strconv.Unquote(line)
+
+			if err != nil {
+				return fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+
+			*field += value
+			continue
+		}
+
+		keyword, rest, ok := strings.Cut(line, " ")
+
+		if !ok {
+			return fmt.Errorf("line %d: invalid line", lineNumber)
+		}
+
+		value, err := strconv.Unquote(strings.TrimSpace(rest))
+
+		if err != nil {
+			return fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		switch {
+		case keyword == "msgctxt":
+			if inEntry && msgid != "" {
+				flush()
+			}
+			inEntry = true
+			msgctxt = value
+			field = &msgctxt
+		case keyword == "msgid":
+			if inEntry && msgid != "" {
+				flush()
+			}
+			inEntry = true
+			msgid = value
+			field = &msgid
+		case keyword == "msgid_plural":
+			// the plural ID isn't relevant for lookups
+			field = new(string)
+		case keyword == "msgstr":
+			forms = []string{value}
+			field = &forms[0]
+		case strings.HasPrefix(keyword, "msgstr[") && strings.HasSuffix(keyword, "]"):
+
+			i, err := strconv.Atoi(keyword[7 : len(keyword)-1])
+
+			if err != nil || i < 0 || i > len(forms) {
+				return fmt.Errorf("line %d: invalid plural index", lineNumber)
+			}
+
+			if i == len(forms) {
+				forms = append(forms, value)
+			} else {
+				forms[i] = value
+			}
+
+			field = &forms[i]
+		default:
+			return fmt.Errorf("line %d: unknown keyword '%s'", lineNumber, keyword)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	flush()
+
+	return nil
+}
@@ -0,0 +1,205 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package i18n
+
+import (
+	"github.com/gospel-sh/gospel"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type numberFormat struct {
+	decimal string
+	group   string
+}
+
+var numberFormats = map[string]numberFormat{
+	"en": {".", ","},
+	"de": {",", "."},
+	"es": {",", "."},
+	"it": {",", "."},
+	"nl": {",", "."},
+	"pt": {",", "."},
+	"da": {",", "."},
+	"tr": {",", "."},
+	"fr": {",", "\u202f"},
+	"ru": {",", "\u00a0"},
+	"pl": {",", "\u00a0"},
+	"cs": {",", "\u00a0"},
+	"sv": {",", "\u00a0"},
+	"nb": {",", "\u00a0"},
+	"fi": {",", "\u00a0"},
+	"uk": {",", "\u00a0"},
+	"ja": {".", ","},
+	"zh": {".", ","},
+	"ko": {".", ","},
+	// Switzerland uses a different group separator than Germany
+	"de-ch": {".", "\u2019"},
+}
+
+func lookupNumberFormat(locale string) numberFormat {
+	if format, ok := numberFormats[Normalize(locale)]; ok {
+		return format
+	}
+	if format, ok := numberFormats[Language(locale)]; ok {
+		return format
+	}
+	return numberFormats["en"]
+}
+
+// Formats a number with the given number of decimals, using the decimal
+// and group separators of the locale
+func FormatNumber(locale string, value float64, decimals int) string {
+
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+
+	format := lookupNumberFormat(locale)
+	formatted := strconv.FormatFloat(math.Abs(value), 'f', decimals, 64)
+	integer, fraction, _ := strings.Cut(formatted, ".")
+
+	var b strings.Builder
+
+	if value < 0 && strings.Trim(formatted, "0.") != "" {
+		b.WriteString("-")
+	}
+
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteString(format.group)
+		}
+		b.WriteRune(digit)
+	}
+
+	if fraction != "" {
+		b.WriteString(format.decimal)
+		b.WriteString(fraction)
+	}
+
+	return b.String()
+}
+
+type DateStyle int
+
+const (
+	ShortDate DateStyle = iota
+	MediumDate
+	LongDate
+)
+
+type dateFormat struct {
+	// Go layouts, with "January" and "Jan" replaced by the localized names
+	layouts [3]string
+	months  [12]string
+	// abbreviated month names
+	short [12]string
+}
+
+var dateFormats = map[string]dateFormat{
+	"en": {
+		layouts: [3]string{"1/2/06", "Jan 2, 2006", "January 2, 2006"},
+		months:  [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		short:   [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+	},
+	"en-gb": {
+		layouts: [3]string{"02/01/2006", "2 Jan 2006", "2 January 2006"},
+		months:  [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		short:   [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sept", "Oct", "Nov", "Dec"},
+	},
+	"de": {
+		layouts: [3]string{"02.01.06", "02.01.2006", "2. January 2006"},
+		months:  [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		short:   [12]string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+	},
+	"fr": {
+		layouts: [3]string{"02/01/2006", "2 Jan 2006", "2 January 2006"},
+		months:  [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		short:   [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+	},
+	"es": {
+		layouts: [3]string{"2/1/06", "2 Jan 2006", "2 de January de 2006"},
+		months:  [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		short:   [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+	},
+	"it": {
+		layouts: [3]string{"02/01/06", "2 Jan 2006", "2 January 2006"},
+		months:  [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+		short:   [12]string{"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic"},
+	},
+	"nl": {
+		layouts: [3]string{"02-01-2006", "2 Jan 2006", "2 January 2006"},
+		months:  [12]string{"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+		short:   [12]string{"jan", "feb", "mrt", "apr", "mei", "jun", "jul", "aug", "sep", "okt", "nov", "dec"},
+	},
+	"ja": {
+		layouts: [3]string{"2006/01/02", "2006/01/02", "2006年1月2日"},
+	},
+	"zh": {
+		layouts: [3]string{"2006/1/2", "2006年1月2日", "2006年1月2日"},
+	},
+}
+
+func lookupDateFormat(locale string) dateFormat {
+	if format, ok := dateFormats[Normalize(locale)]; ok {
+		return format
+	}
+	if format, ok := dateFormats[Language(locale)]; ok {
+		return format
+	}
+	return dateFormats["en"]
+}
+
+// we use placeholders that can't occur in the output of time.Format
+const (
+	monthPlaceholder      = "\x00M\x00"
+	shortMonthPlaceholder = "\x00m\x00"
+)
+
+// Formats a date in the style of the locale
+func FormatDate(locale string, t time.Time, style DateStyle) string {
+
+	format := lookupDateFormat(locale)
+
+	if style < ShortDate || style > LongDate {
+		style = MediumDate
+	}
+
+	layout := format.layouts[style]
+
+	// we replace month names ourselves, as Go only knows the English ones
+	layout = strings.Replace(layout, "January", monthPlaceholder, 1)
+	layout = strings.Replace(layout, "Jan", shortMonthPlaceholder, 1)
+
+	formatted := t.Format(layout)
+
+	month := t.Month() - 1
+	formatted = strings.Replace(formatted, monthPlaceholder, format.months[month], 1)
+	formatted = strings.Replace(formatted, shortMonthPlaceholder, format.short[month], 1)
+
+	return formatted
+}
+
+// Formats a number for the locale of the current request
+func Number(c gospel.Context, value float64, decimals int) string {
+	return FormatNumber(Locale(c), value, decimals)
+}
+
+// Formats a date for the locale of the current request
+func Date(c gospel.Context, t time.Time, style DateStyle) string {
+	return FormatDate(Locale(c), t, style)
+}
@@ -0,0 +1,201 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package i18n
+
+import (
+	"github.com/gospel-sh/gospel"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	bundleKey = "i18n.bundle"
+	localeKey = "i18n.locale"
+)
+
+// Configures how we determine the locale of a request
+type Negotiation struct {
+	// the name of the cookie that stores the locale, if empty we don't use cookies
+	Cookie string
+	// whether we look for a locale prefix in the path (e.g. "/de/about")
+	RoutePrefix bool
+}
+
+var DefaultNegotiation = Negotiation{
+	Cookie:      "locale",
+	RoutePrefix: true,
+}
+
+// Wraps the root element function so that all elements can use the bundle
+// and the negotiated locale. The locale is determined by the route prefix,
+// the locale cookie and the Accept-Language header (in that order), and
+// falls back to the default locale of the bundle.
+func Localized(bundle *Bundle, negotiation Negotiation, root gospel.ElementFunction) gospel.ElementFunction {
+	return func(c gospel.Context) gospel.Element {
+
+		gospel.GlobalVar(c, bundleKey, bundle)
+
+		locale := ""
+		request := c.Request()
+
+		if negotiation.RoutePrefix {
+
+			var prefix string
+
+			if locale, prefix = routeLocale(bundle, request.URL.Path); locale != "" {
+				// routes are matched after the locale prefix
+				if router := gospel.UseRouter(c); router != nil {
+					router.SetPrefix(prefix)
+				}
+			}
+		}
+
+		if locale == "" && negotiation.Cookie != "" {
+			if cookie, err := request.Cookie(negotiation.Cookie); err == nil {
+				locale = bundle.Match(cookie.Value)
+			}
+		}
+
+		if locale == "" {
+			locale = Negotiate(bundle, request.Header.Get("Accept-Language"))
+		}
+
+		if locale == "" {
+			locale = bundle.DefaultLocale
+		}
+
+		gospel.GlobalVar(c, localeKey, locale)
+
+		return root(c)
+	}
+}
+
+func routeLocale(bundle *Bundle, path string) (string, string) {
+
+	segment := strings.TrimPrefix(path, "/")
+
+	if i := strings.Index(segment, "/"); i != -1 {
+		segment = segment[:i]
+	}
+
+	if segment == "" || !bundle.Supports(segment) {
+		return "", ""
+	}
+
+	return Normalize(segment), "/" + segment
+}
+
+type languageRange struct {
+	tag     string
+	quality float64
+}
+
+// Returns the best supported locale for an Accept-Language header, or an
+// empty string if none of the languages is supported
+func Negotiate(bundle *Bundle, acceptLanguage string) string {
+
+	ranges := make([]languageRange, 0)
+
+	for _, part := range strings.Split(acceptLanguage, ",") {
+
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if value, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = value
+			}
+		}
+
+		if quality > 0 {
+			ranges = append(ranges, languageRange{tag, quality})
+		}
+	}
+
+	// we keep the order of the header for equal qualities
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].quality > ranges[j].quality
+	})
+
+	for _, r := range ranges {
+		if locale := bundle.Match(r.tag); locale != "" {
+			return locale
+		}
+	}
+
+	return ""
+}
+
+// Changes the locale for the rest of the request and stores it in the cookie
+// of the given negotiation (if any)
+func SetLocale(c gospel.Context, negotiation Negotiation, locale string) {
+
+	locale = Normalize(locale)
+
+	if negotiation.Cookie != "" {
+		http.SetCookie(c.ResponseWriter(), &http.Cookie{
+			Name:     negotiation.Cookie,
+			Value:    locale,
+			Path:     "/",
+			Expires:  time.Now().Add(365 * 24 * time.Hour),
+			SameSite: http.SameSiteLaxMode,
+		})
+	}
+
+	if variable := c.GetVar(localeKey); variable != nil {
+		variable.Set(locale)
+	} else {
+		gospel.GlobalVar(c, localeKey, locale)
+	}
+}
+
+// Returns the locale of the current request
+func Locale(c gospel.Context) string {
+
+	if locale := gospel.UseGlobal[string](c, localeKey); locale != "" {
+		return locale
+	}
+
+	if bundle := UseBundle(c); bundle != nil {
+		return bundle.DefaultLocale
+	}
+
+	return ""
+}
+
+func UseBundle(c gospel.Context) *Bundle {
+	return gospel.UseGlobal[*Bundle](c, bundleKey)
+}
+
+// Translates a message for the locale of the current request
+func T(c gospel.Context, key string, args ...any) string {
+
+	bundle := UseBundle(c)
+
+	if bundle == nil {
+		gospel.Log.Warning("No message bundle, did you wrap your app with i18n.Localized?")
+		return key
+	}
+
+	return bundle.Translate(Locale(c), key, args...)
+}
@@ -0,0 +1,203 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package i18n
+
+import (
+	"github.com/gospel-sh/gospel"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+var testCatalogs = fstest.MapFS{
+	"locales/en.json": {Data: []byte(`{
+		"greeting": "Hello %s",
+		"items": {"one": "%d item", "other": "%d items"},
+		"files": {"one": "One file in %[2]s", "other": "%[1]d files in %[2]s"},
+		"messages": {"one": "One new message", "other": "%d new messages"},
+		"nav": {"home": "Home"}
+	}`)},
+	"locales/ru.po": {Data: []byte(`
+msgid ""
+msgstr ""
+"Plural-Forms: nplurals=3;\n"
+
+# items in a list
+msgid "items"
+msgid_plural "items"
+msgstr[0] "%d предмет"
+msgstr[1] "%d предмета"
+msgstr[2] "%d "
+"предметов"
+`)},
+	"locales/de.json": {Data: []byte(`{"greeting": "Hallo %s"}`)},
+}
+
+func makeTestBundle(t *testing.T) *Bundle {
+	bundle := MakeBundle("en")
+
+	if err := bundle.LoadFS(testCatalogs, "locales"); err != nil {
+		t.Fatal(err)
+	}
+
+	return bundle
+}
+
+func TestTranslate(t *testing.T) {
+
+	bundle := makeTestBundle(t)
+
+	for _, test := range []struct {
+		locale   string
+		key      string
+		args     []any
+		expected string
+	}{
+		{"en", "greeting", []any{"Bob"}, "Hello Bob"},
+		{"de-AT", "greeting", []any{"Bob"}, "Hallo Bob"},
+		{"en", "items", []any{1}, "1 item"},
+		{"en", "items", []any{3}, "3 items"},
+		{"ru", "items", []any{21}, "21 предмет"},
+		{"ru", "items", []any{3}, "3 предмета"},
+		{"ru", "items", []any{11}, "11 предметов"},
+		// we fall back to the default locale
+		{"de", "items", []any{2}, "2 items"},
+		// forms only get the arguments they use
+		{"en", "messages", []any{1}, "One new message"},
+		{"en", "messages", []any{2}, "2 new messages"},
+		{"en", "files", []any{1, "docs"}, "One file in docs"},
+		{"en", "files", []any{3, "docs"}, "3 files in docs"},
+		{"en", "nav.home", nil, "Home"},
+		{"en", "missing", nil, "missing"},
+	} {
+		if result := bundle.Translate(test.locale, test.key, test.args...); result != test.expected {
+			t.Errorf("%s/%s: expected '%s', got '%s'", test.locale, test.key, test.expected, result)
+		}
+	}
+}
+
+func TestArguments(t *testing.T) {
+	for format, expected := range map[string]int{
+		"One item":       0,
+		"100%% done":     0,
+		"%d items":       1,
+		"%s: %d items":   2,
+		"%[2]s":          2,
+		"%[2]s %[1]d":    2,
+		"%*d":            2,
+		"%-8.2f%%":       1,
+		"%[1]d %[1]x %v": 2,
+	} {
+		if n := arguments(format); n != expected {
+			t.Errorf("%q: expected %d arguments, got %d", format, expected, n)
+		}
+	}
+}
+
+func TestRegisterPluralRule(t *testing.T) {
+
+	done := make(chan struct{})
+
+	// rules can be registered while others are being used
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			RegisterPluralRule("x-test", onlyOther)
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		Plural("en")
+	}
+
+	<-done
+
+	if Plural("x-test") != onlyOther {
+		t.Fatalf("expected the registered rule")
+	}
+}
+
+func TestNegotiation(t *testing.T) {
+
+	bundle := makeTestBundle(t)
+
+	if locale := Negotiate(bundle, "fr-CH, fr;q=0.9, de-DE;q=0.8, en;q=0.7"); locale != "de" {
+		t.Fatalf("expected 'de', got '%s'", locale)
+	}
+
+	for _, test := range []struct {
+		path     string
+		cookie   string
+		header   string
+		expected string
+	}{
+		{"/ru/about", "de", "en", "ru"},
+		{"/about", "de", "ru", "de"},
+		{"/about", "", "ru;q=0.5, de", "de"},
+		{"/about", "", "fr", "en"},
+	} {
+
+		r := httptest.NewRequest("GET", test.path, nil)
+		r.Header.Set("Accept-Language", test.header)
+
+		if test.cookie != "" {
+			r.Header.Set("Cookie", "locale="+test.cookie)
+		}
+
+		c := gospel.MakeDefaultContext(r, httptest.NewRecorder(), gospel.MakeStore(gospel.MakeCookieStore("")))
+		router := gospel.MakeRouter(c)
+
+		var locale string
+
+		c.Execute(Localized(bundle, DefaultNegotiation, func(c gospel.Context) gospel.Element {
+			locale = Locale(c)
+			return nil
+		}))
+
+		if locale != test.expected {
+			t.Errorf("%s: expected '%s', got '%s'", test.path, test.expected, locale)
+		}
+
+		if test.expected == "ru" && router.Prefix() != "/ru" {
+			t.Errorf("expected the router prefix to be set")
+		}
+	}
+}
+
+func TestFormatting(t *testing.T) {
+
+	if n := FormatNumber("de", -1234567.891, 2); n != "-1.234.567,89" {
+		t.Errorf("unexpected number: %s", n)
+	}
+
+	if n := FormatNumber("en-US", 1234.6, 0); n != "1,235" {
+		t.Errorf("unexpected number: %s", n)
+	}
+
+	date := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+
+	if d := FormatDate("de", date, LongDate); d != "5. März 2024" {
+		t.Errorf("unexpected date: %s", d)
+	}
+
+	if d := FormatDate("en", date, MediumDate); d != "Mar 5, 2024" {
+		t.Errorf("unexpected date: %s", d)
+	}
+
+	if d := FormatDate("fr", date, ShortDate); d != "05/03/2024" {
+		t.Errorf("unexpected date: %s", d)
+	}
+}
@@ -0,0 +1,170 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package i18n
+
+import (
+	"sync"
+)
+
+// Selects the plural form for a count. Categories lists the CLDR plural
+// categories of the language in the order of their forms, which is also the
+// order used by gettext (PO) files.
+type PluralRule struct {
+	Categories []string
+	Index      func(n int64) int
+}
+
+var oneOther = &PluralRule{
+	Categories: []string{"one", "other"},
+	Index: func(n int64) int {
+		if n == 1 {
+			return 0
+		}
+		return 1
+	},
+}
+
+var zeroOneOther = &PluralRule{
+	Categories: []string{"one", "other"},
+	Index: func(n int64) int {
+		if n == 0 || n == 1 {
+			return 0
+		}
+		return 1
+	},
+}
+
+var onlyOther = &PluralRule{
+	Categories: []string{"other"},
+	Index: func(n int64) int {
+		return 0
+	},
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+var eastSlavic = &PluralRule{
+	Categories: []string{"one", "few", "many"},
+	Index: func(n int64) int {
+		n = abs(n)
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return 0
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return 1
+		}
+		return 2
+	},
+}
+
+var polish = &PluralRule{
+	Categories: []string{"one", "few", "many"},
+	Index: func(n int64) int {
+		n = abs(n)
+		switch {
+		case n == 1:
+			return 0
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return 1
+		}
+		return 2
+	},
+}
+
+var westSlavic = &PluralRule{
+	Categories: []string{"one", "few", "other"},
+	Index: func(n int64) int {
+		n = abs(n)
+		switch {
+		case n == 1:
+			return 0
+		case n >= 2 && n <= 4:
+			return 1
+		}
+		return 2
+	},
+}
+
+var arabic = &PluralRule{
+	Categories: []string{"zero", "one", "two", "few", "many", "other"},
+	Index: func(n int64) int {
+		n = abs(n)
+		switch {
+		case n == 0:
+			return 0
+		case n == 1:
+			return 1
+		case n == 2:
+			return 2
+		case n%100 >= 3 && n%100 <= 10:
+			return 3
+		case n%100 >= 11:
+			return 4
+		}
+		return 5
+	},
+}
+
+var pluralRulesMutex sync.RWMutex
+var pluralRules = map[string]*PluralRule{
+	"fr": zeroOneOther,
+	"pt": zeroOneOther,
+	"ru": eastSlavic,
+	"uk": eastSlavic,
+	"be": eastSlavic,
+	"pl": polish,
+	"cs": westSlavic,
+	"sk": westSlavic,
+	"ar": arabic,
+	"ja": onlyOther,
+	"zh": onlyOther,
+	"ko": onlyOther,
+	"vi": onlyOther,
+	"th": onlyOther,
+	"id": onlyOther,
+}
+
+// Registers the plural rule for a language (e.g. "de")
+func RegisterPluralRule(language string, rule *PluralRule) {
+	pluralRulesMutex.Lock()
+	defer pluralRulesMutex.Unlock()
+	pluralRules[Normalize(language)] = rule
+}
+
+// Returns the plural rule for a locale, which defaults to the English one
+func Plural(locale string) *PluralRule {
+	pluralRulesMutex.RLock()
+	defer pluralRulesMutex.RUnlock()
+	if rule, ok := pluralRules[Normalize(locale)]; ok {
+		return rule
+	}
+	if rule, ok := pluralRules[Language(locale)]; ok {
+		return rule
+	}
+	return oneOther
+}
+
+func isCategory(name string) bool {
+	switch name {
+	case "zero", "one", "two", "few", "many", "other":
+		return true
+	}
+	return false
+}
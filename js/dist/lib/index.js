@@ -9,8 +9,29 @@ var __awaiter = (this && this.__awaiter) || function (thisArg, _arguments, P, ge
 };
 import * as reactive_1 from './reactive.js';
 export { reactive_1 as reactive };
+function callHandler(id, event, formData) {
+    return __awaiter(this, void 0, void 0, function* () {
+        // we identify the function and the event type
+        formData.append('_gspl', id);
+        formData.append('_gspl_event', event);
+        const response = yield fetch(document.location.href, {
+            method: 'post',
+            body: formData,
+        });
+        replaceDom(response.url, yield response.text(), response.url !== document.location.href);
+    });
+}
 function handleClick(e) {
     let target = e.target;
+    if (target instanceof Element) {
+        // elements with a server-side click handler take precedence over links
+        const handler = target.closest('[gospel-onclick]');
+        if (handler !== null) {
+            e.preventDefault();
+            callHandler(handler.getAttribute('gospel-onclick'), 'click', new FormData());
+            return;
+        }
+    }
     if (target instanceof HTMLElement && target.tagName !== 'a') {
         target = target.closest('a');
     }
@@ -29,6 +50,26 @@ function handleClick(e) {
     e.preventDefault();
     navigateTo(link, true);
 }
+function handleChange(e) {
+    if (!(e.target instanceof Element))
+        return;
+    const handler = e.target.closest('[gospel-onchange]');
+    if (handler === null)
+        return;
+    const formData = new FormData();
+    const input = handler;
+    // we send the new value of the element along
+    if (input.name !== "") {
+        if (input.type === 'checkbox' || input.type === 'radio') {
+            if (input.checked)
+                formData.append(input.name, input.value);
+        }
+        else {
+            formData.append(input.name, input.value);
+        }
+    }
+    callHandler(handler.getAttribute('gospel-onchange'), 'change', formData);
+}
 function handlePopState(_) {
     navigateTo(document.location.href, false);
 }
@@ -107,6 +148,7 @@ function replaceDom(link, text, push) {
 }
 function addEventListeners() {
     addEventListener('click', handleClick);
+    addEventListener('change', handleChange);
     addEventListener('popstate', handlePopState);
 }
 function initDocument() {
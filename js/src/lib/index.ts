@@ -1,7 +1,33 @@
 export * as reactive from './reactive.js';
 
+async function callHandler(id: string, event: string, formData: FormData){
+
+	// we identify the function and the event type
+	formData.append('_gspl', id);
+	formData.append('_gspl_event', event);
+
+	const response = await fetch(document.location.href, {
+		method: 'post',
+		body: formData,
+	});
+
+	replaceDom(response.url, await response.text(), response.url !== document.location.href);
+}
+
 function handleClick(e: Event){
 	let target = e.target;
+
+	if (target instanceof Element){
+		// elements with a server-side click handler take precedence over links
+		const handler = target.closest('[gospel-onclick]');
+
+		if (handler !== null){
+			e.preventDefault();
+			callHandler(handler.getAttribute('gospel-onclick')!, 'click', new FormData());
+			return;
+		}
+	}
+
 	if (target instanceof HTMLElement && target.tagName !== 'a'){
 		target = target.closest('a');
 	}
@@ -30,6 +56,32 @@ function handleClick(e: Event){
 
 }
 
+function handleChange(e: Event){
+
+	if (!(e.target instanceof Element))
+		return;
+
+	const handler = e.target.closest('[gospel-onchange]');
+
+	if (handler === null)
+		return;
+
+	const formData = new FormData();
+	const input = handler as HTMLInputElement;
+
+	// we send the new value of the element along
+	if (input.name !== ""){
+		if (input.type === 'checkbox' || input.type === 'radio'){
+			if (input.checked)
+				formData.append(input.name, input.value);
+		} else {
+			formData.append(input.name, input.value);
+		}
+	}
+
+	callHandler(handler.getAttribute('gospel-onchange')!, 'change', formData);
+}
+
 function handlePopState(_: PopStateEvent){
 	navigateTo(document.location.href, false);
 }
@@ -134,6 +186,7 @@ function replaceDom(link: string, text: string, push: boolean) {
 function addEventListeners(){
 
 	addEventListener('click', handleClick);
+	addEventListener('change', handleChange);
 	addEventListener('popstate', handlePopState);
 
 }
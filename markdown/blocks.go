@@ -0,0 +1,824 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package markdown
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type blockKind int
+
+const (
+	paragraphBlock blockKind = iota
+	headingBlock
+	thematicBreakBlock
+	codeBlock
+	htmlBlock
+	blockquoteBlock
+	listBlock
+	listItemBlock
+	tableBlock
+)
+
+type block struct {
+	kind     blockKind
+	children []*block
+	// inline content of paragraphs, headings and table cells, or the
+	// content of code and HTML blocks
+	text string
+	// the heading level
+	level int
+	// the info string of fenced code blocks
+	info string
+	// lists
+	ordered bool
+	bullet  byte
+	start   int
+	tight   bool
+	// task list items
+	task    bool
+	checked bool
+	// tables
+	align []string
+	rows  [][]string
+	// whether the block is followed by a blank line
+	blankAfter bool
+}
+
+type reference struct {
+	destination string
+	title       string
+}
+
+type blockParser struct {
+	references map[string]reference
+	// whether to parse GFM tables
+	tables bool
+}
+
+var (
+	atxHeadingRegexp    = regexp.MustCompile(`^(#{1,6})(?:[ \t]+|$)`)
+	atxClosingRegexp    = regexp.MustCompile(`(?:^|[ \t]+)#+[ \t]*$`)
+	thematicBreakRegexp = regexp.MustCompile(`^(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	setextRegexp        = regexp.MustCompile(`^(?:=+|-+)[ \t]*$`)
+	fenceRegexp         = regexp.MustCompile("^(`{3,}|~{3,})(.*)$")
+	bulletRegexp        = regexp.MustCompile(`^([-+*])(?:[ \t]|$)`)
+	orderedRegexp       = regexp.MustCompile(`^([0-9]{1,9})([.)])(?:[ \t]|$)`)
+	tableDelimiterCell  = regexp.MustCompile(`^:?-+:?$`)
+
+	htmlBlockStart = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)^<(?:script|pre|style|textarea)(?:\s|>|$)`),
+		regexp.MustCompile(`^<!--`),
+		regexp.MustCompile(`^<\?`),
+		regexp.MustCompile(`^<![a-zA-Z]`),
+		regexp.MustCompile(`^<!\[CDATA\[`),
+		regexp.MustCompile(`(?i)^</?(?:address|article|aside|base|basefont|blockquote|body|caption|center|col|colgroup|dd|details|dialog|dir|div|dl|dt|fieldset|figcaption|figure|footer|form|frame|frameset|h[1-6]|head|header|hr|html|iframe|legend|li|link|main|menu|menuitem|nav|noframes|ol|optgroup|option|p|param|search|section|summary|table|tbody|td|tfoot|th|thead|title|tr|track|ul)(?:\s|/?>|$)`),
+		regexp.MustCompile(`^(?:` + openTag + `|` + closingTag + `)[ \t]*$`),
+	}
+
+	htmlBlockEnd = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)</(?:script|pre|style|textarea)>`),
+		regexp.MustCompile(`-->`),
+		regexp.MustCompile(`\?>`),
+		regexp.MustCompile(`>`),
+		regexp.MustCompile(`\]\]>`),
+	}
+)
+
+// Returns the width of the leading whitespace of a line in columns
+func indentation(line string) int {
+	return indentColumn(line, 0)
+}
+
+// Returns the column after the leading whitespace of a line that starts at the
+// given column, tabs advance to the next multiple of 4
+func indentColumn(line string, column int) int {
+
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case ' ':
+			column++
+		case '\t':
+			column += 4 - column%4
+		default:
+			return column
+		}
+	}
+
+	return column
+}
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+// Removes up to n columns of indentation
+func unindent(line string, n int) string {
+	return unindentFrom(line, 0, n)
+}
+
+// Removes the leading whitespace of a line that starts at the given column up
+// to column n, the rest of the line is kept as is except for a partially
+// removed tab, which becomes the remaining spaces
+func unindentFrom(line string, column, n int) string {
+
+	for i := 0; i < len(line); i++ {
+
+		if column >= n {
+			return line[i:]
+		}
+
+		switch line[i] {
+		case ' ':
+			column++
+		case '\t':
+			column += 4 - column%4
+			if column > n {
+				return strings.Repeat(" ", column-n) + line[i+1:]
+			}
+		default:
+			return line[i:]
+		}
+	}
+
+	return ""
+}
+
+type listMarker struct {
+	ordered bool
+	bullet  byte
+	start   int
+	// the length of the marker itself
+	length int
+	// the width of the marker including the following spaces
+	width int
+	empty bool
+}
+
+func parseListMarker(line string) (listMarker, bool) {
+
+	var marker listMarker
+	var match []string
+
+	if match = bulletRegexp.FindStringSubmatch(line); match != nil {
+		marker.bullet = match[1][0]
+	} else if match = orderedRegexp.FindStringSubmatch(line); match != nil {
+		marker.ordered = true
+		marker.bullet = match[2][0]
+		marker.start, _ = strconv.Atoi(match[1])
+	} else {
+		return marker, false
+	}
+
+	markerLength := len(match[1])
+
+	if marker.ordered {
+		markerLength++
+	}
+
+	marker.length = markerLength
+	rest := line[markerLength:]
+
+	if isBlank(rest) {
+		marker.empty = true
+		marker.width = markerLength + 1
+		return marker, true
+	}
+
+	spaces := indentColumn(rest, markerLength) - markerLength
+
+	// with 5 or more spaces, the content is an indented code block
+	if spaces > 4 {
+		spaces = 1
+	}
+
+	marker.width = markerLength + spaces
+
+	return marker, true
+}
+
+func fenceStart(line string) (string, string, bool) {
+
+	if indentation(line) > 3 {
+		return "", "", false
+	}
+
+	match := fenceRegexp.FindStringSubmatch(strings.TrimLeft(line, " \t"))
+
+	if match == nil {
+		return "", "", false
+	}
+
+	// the info string of backtick fences can't contain backticks
+	if match[1][0] == '`' && strings.Contains(match[2], "`") {
+		return "", "", false
+	}
+
+	return match[1], strings.TrimSpace(match[2]), true
+}
+
+func htmlBlockType(line string) int {
+
+	if indentation(line) > 3 {
+		return -1
+	}
+
+	trimmed := strings.TrimLeft(line, " \t")
+
+	for i, re := range htmlBlockStart {
+		if re.MatchString(trimmed) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Returns whether the line starts a block that can interrupt a paragraph
+func interruptsParagraph(line string) bool {
+
+	if isBlank(line) || indentation(line) > 3 {
+		return false
+	}
+
+	trimmed := strings.TrimLeft(line, " \t")
+
+	if atxHeadingRegexp.MatchString(trimmed) || thematicBreakRegexp.MatchString(trimmed) || strings.HasPrefix(trimmed, ">") {
+		return true
+	}
+
+	if _, _, ok := fenceStart(line); ok {
+		return true
+	}
+
+	// HTML blocks of type 7 can't interrupt paragraphs
+	if t := htmlBlockType(line); t >= 0 && t < 6 {
+		return true
+	}
+
+	// only non-empty lists starting with 1 can interrupt paragraphs
+	if marker, ok := parseListMarker(trimmed); ok && !marker.empty && (!marker.ordered || marker.start == 1) {
+		return true
+	}
+
+	return false
+}
+
+func splitLines(source string) []string {
+
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+	source = strings.ReplaceAll(source, "\r", "\n")
+	// insecure characters are replaced
+	source = strings.ReplaceAll(source, "\x00", "�")
+
+	return strings.Split(strings.TrimSuffix(source, "\n"), "\n")
+}
+
+func (p *blockParser) parse(lines []string) []*block {
+
+	blocks := []*block{}
+	var paragraph []string
+
+	add := func(b *block) {
+		blocks = append(blocks, b)
+	}
+
+	flush := func() {
+		if paragraph == nil {
+			return
+		}
+		text := p.extractReferences(strings.Join(paragraph, "\n"))
+		paragraph = nil
+		if text != "" {
+			add(&block{kind: paragraphBlock, text: text})
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+
+		line := lines[i]
+
+		if isBlank(line) {
+			flush()
+			if len(blocks) > 0 {
+				blocks[len(blocks)-1].blankAfter = true
+			}
+			continue
+		}
+
+		indent := indentation(line)
+		trimmed := strings.TrimLeft(line, " \t")
+
+		// indented code blocks can't interrupt paragraphs
+		if indent >= 4 && paragraph == nil {
+
+			j := i
+			end := i
+
+			for ; j < len(lines); j++ {
+				if indentation(lines[j]) >= 4 {
+					end = j + 1
+				} else if !isBlank(lines[j]) {
+					break
+				}
+			}
+
+			codeLines := make([]string, 0, end-i)
+
+			for _, codeLine := range lines[i:end] {
+				codeLines = append(codeLines, unindent(codeLine, 4))
+			}
+
+			add(&block{kind: codeBlock, text: strings.Join(codeLines, "\n") + "\n"})
+			i = end - 1
+			continue
+		}
+
+		if indent >= 4 {
+			// a lazy paragraph continuation
+			paragraph = append(paragraph, trimmed)
+			continue
+		}
+
+		if fence, info, ok := fenceStart(line); ok {
+
+			flush()
+
+			codeLines := []string{}
+			j := i + 1
+
+			for ; j < len(lines); j++ {
+				closing := strings.TrimSpace(lines[j])
+				if indentation(lines[j]) < 4 && strings.HasPrefix(closing, fence) && strings.Trim(closing, fence[:1]) == "" {
+					break
+				}
+				codeLines = append(codeLines, unindent(lines[j], indent))
+			}
+
+			text := strings.Join(codeLines, "\n")
+
+			if len(codeLines) > 0 {
+				text += "\n"
+			}
+
+			add(&block{kind: codeBlock, text: text, info: info})
+			i = j
+			continue
+		}
+
+		if match := atxHeadingRegexp.FindStringSubmatch(trimmed); match != nil {
+			flush()
+			text := strings.TrimSpace(trimmed[len(match[0]):])
+			text = strings.TrimSpace(atxClosingRegexp.ReplaceAllString(text, ""))
+			add(&block{kind: headingBlock, level: len(match[1]), text: text})
+			continue
+		}
+
+		if paragraph != nil && setextRegexp.MatchString(trimmed) {
+
+			text := p.extractReferences(strings.Join(paragraph, "\n"))
+			paragraph = nil
+
+			if text != "" {
+				level := 1
+				if trimmed[0] == '-' {
+					level = 2
+				}
+				add(&block{kind: headingBlock, level: level, text: text})
+				continue
+			}
+
+			// the paragraph only contained link reference definitions
+		}
+
+		if thematicBreakRegexp.MatchString(trimmed) {
+			flush()
+			add(&block{kind: thematicBreakBlock})
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, ">") {
+			flush()
+			end, quoteLines := p.collectBlockquote(lines, i)
+			add(&block{kind: blockquoteBlock, children: p.parse(quoteLines)})
+			i = end - 1
+			continue
+		}
+
+		if marker, ok := parseListMarker(trimmed); ok {
+			// lists can only interrupt paragraphs under certain conditions
+			if paragraph == nil || (!marker.empty && (!marker.ordered || marker.start == 1)) {
+				flush()
+				end, list := p.parseList(lines, i)
+				add(list)
+				i = end - 1
+				continue
+			}
+		}
+
+		if t := htmlBlockType(line); t >= 0 && (paragraph == nil || t < 6) {
+
+			flush()
+
+			j := i
+
+			if t < 5 {
+				// the block ends with the line containing the end condition
+				for ; j < len(lines); j++ {
+					if htmlBlockEnd[t].MatchString(lines[j]) {
+						break
+					}
+				}
+				if j == len(lines) {
+					j--
+				}
+			} else {
+				// the block ends with a blank line
+				for j+1 < len(lines) && !isBlank(lines[j+1]) {
+					j++
+				}
+			}
+
+			add(&block{kind: htmlBlock, text: strings.Join(lines[i:j+1], "\n")})
+			i = j
+			continue
+		}
+
+		if p.tables && len(paragraph) == 1 {
+			if table, end, ok := p.parseTable(paragraph[0], lines, i); ok {
+				paragraph = nil
+				add(table)
+				i = end - 1
+				continue
+			}
+		}
+
+		if paragraph != nil && interruptsParagraph(line) {
+			flush()
+		}
+
+		paragraph = append(paragraph, trimmed)
+	}
+
+	flush()
+
+	return blocks
+}
+
+// Returns whether a non-blank line that isn't part of a container continues
+// the paragraph at the end of the given lines (a "lazy continuation line")
+func isLazyContinuation(lines []string, line string) bool {
+
+	if len(lines) == 0 || isBlank(lines[len(lines)-1]) || interruptsParagraph(line) {
+		return false
+	}
+
+	last := strings.TrimLeft(lines[len(lines)-1], " \t")
+
+	// the last line has to be part of a paragraph
+	if _, _, ok := fenceStart(last); ok || strings.HasPrefix(last, ">") && isBlank(last[1:]) {
+		return false
+	}
+
+	return indentation(lines[len(lines)-1]) < 4 && !setextRegexp.MatchString(last)
+}
+
+func (p *blockParser) collectBlockquote(lines []string, start int) (int, []string) {
+
+	quoteLines := []string{}
+	i := start
+
+	for ; i < len(lines); i++ {
+
+		line := lines[i]
+		trimmed := strings.TrimLeft(line, " \t")
+
+		if indentation(line) < 4 && strings.HasPrefix(trimmed, ">") {
+			// the optional space after the marker can be part of a tab
+			quoteLines = append(quoteLines, unindentFrom(trimmed[1:], 1, 2))
+			continue
+		}
+
+		if !isBlank(line) && isLazyContinuation(quoteLines, line) {
+			quoteLines = append(quoteLines, trimmed)
+			continue
+		}
+
+		break
+	}
+
+	return i, quoteLines
+}
+
+func (p *blockParser) parseList(lines []string, start int) (int, *block) {
+
+	first, _ := parseListMarker(strings.TrimLeft(lines[start], " \t"))
+
+	list := &block{
+		kind:    listBlock,
+		ordered: first.ordered,
+		bullet:  first.bullet,
+		start:   first.start,
+		tight:   true,
+	}
+
+	i := start
+
+	for i < len(lines) {
+
+		line := lines[i]
+		indent := indentation(line)
+
+		if indent >= 4 {
+			break
+		}
+
+		trimmed := strings.TrimLeft(line, " \t")
+
+		if thematicBreakRegexp.MatchString(trimmed) {
+			break
+		}
+
+		marker, ok := parseListMarker(trimmed)
+
+		if !ok || marker.ordered != list.ordered || marker.bullet != list.bullet {
+			break
+		}
+
+		width := indent + marker.width
+		itemLines := []string{itemContent(trimmed, marker)}
+		i++
+
+		for ; i < len(lines); i++ {
+
+			line := lines[i]
+
+			if isBlank(line) {
+				// an item can begin with at most one blank line
+				if marker.empty && len(itemLines) == 1 && isBlank(itemLines[0]) {
+					break
+				}
+				itemLines = append(itemLines, "")
+				continue
+			}
+
+			if indentation(line) >= width {
+				itemLines = append(itemLines, unindent(line, width))
+				continue
+			}
+
+			// the next item of the list (or of another one)
+			if _, ok := parseListMarker(strings.TrimLeft(line, " \t")); ok {
+				break
+			}
+
+			if isLazyContinuation(itemLines, line) {
+				itemLines = append(itemLines, strings.TrimLeft(line, " \t"))
+				continue
+			}
+
+			break
+		}
+
+		// trailing blank lines don't belong to the item, the first line is
+		// the one with the marker though
+		blank := false
+
+		for len(itemLines) > 1 && isBlank(itemLines[len(itemLines)-1]) {
+			itemLines = itemLines[:len(itemLines)-1]
+			i--
+			blank = true
+		}
+
+		// we skip the blank lines again if the list continues
+		j := i
+
+		for j < len(lines) && isBlank(lines[j]) {
+			j++
+		}
+
+		item := &block{
+			kind:     listItemBlock,
+			children: p.parse(itemLines),
+		}
+
+		// a blank line between the blocks of an item makes the list loose
+		for k, child := range item.children {
+			if child.blankAfter && k < len(item.children)-1 {
+				list.tight = false
+			}
+		}
+
+		list.children = append(list.children, item)
+
+		if blank && j < len(lines) {
+
+			if next, ok := parseListMarker(strings.TrimLeft(lines[j], " \t")); ok && indentation(lines[j]) < 4 && next.ordered == list.ordered && next.bullet == list.bullet && !thematicBreakRegexp.MatchString(strings.TrimLeft(lines[j], " \t")) {
+				// a blank line between items makes the list loose as well
+				list.tight = false
+				i = j
+				continue
+			}
+
+			break
+		}
+	}
+
+	return i, list
+}
+
+// Returns the content of the first line of a list item
+func itemContent(line string, marker listMarker) string {
+	if marker.empty {
+		return ""
+	}
+	return unindentFrom(line[marker.length:], marker.length, marker.width)
+}
+
+func splitTableRow(line string) []string {
+
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+
+	cells := []string{}
+	var cell strings.Builder
+
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			// escaped pipes are part of the cell
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// Parses a GFM table, the header row was already added to a paragraph
+func (p *blockParser) parseTable(header string, lines []string, start int) (*block, int, bool) {
+
+	delimiters := splitTableRow(lines[start])
+	headers := splitTableRow(header)
+
+	if len(delimiters) != len(headers) || !strings.Contains(lines[start], "-") {
+		return nil, 0, false
+	}
+
+	align := make([]string, len(delimiters))
+
+	for i, delimiter := range delimiters {
+
+		if !tableDelimiterCell.MatchString(delimiter) {
+			return nil, 0, false
+		}
+
+		left, right := strings.HasPrefix(delimiter, ":"), strings.HasSuffix(delimiter, ":")
+
+		switch {
+		case left && right:
+			align[i] = "center"
+		case left:
+			align[i] = "left"
+		case right:
+			align[i] = "right"
+		}
+	}
+
+	// header rows need a pipe, unless there are several columns
+	if len(headers) == 1 && !strings.Contains(header, "|") {
+		return nil, 0, false
+	}
+
+	table := &block{
+		kind:  tableBlock,
+		align: align,
+		rows:  [][]string{headers},
+	}
+
+	i := start + 1
+
+	for ; i < len(lines); i++ {
+
+		if isBlank(lines[i]) || interruptsParagraph(lines[i]) {
+			break
+		}
+
+		cells := splitTableRow(lines[i])
+		row := make([]string, len(align))
+
+		// excess cells are ignored, missing ones are empty
+		copy(row, cells)
+		table.rows = append(table.rows, row)
+	}
+
+	return table, i, true
+}
+
+// Removes link reference definitions from the beginning of a paragraph
+func (p *blockParser) extractReferences(text string) string {
+
+	for strings.HasPrefix(text, "[") {
+
+		label, ref, rest, ok := parseReferenceDefinition(text)
+
+		if !ok {
+			break
+		}
+
+		// the first definition of a label takes precedence
+		if _, exists := p.references[label]; !exists {
+			p.references[label] = ref
+		}
+
+		text = rest
+	}
+
+	return strings.TrimSpace(text)
+}
+
+func parseReferenceDefinition(text string) (string, reference, string, bool) {
+
+	var ref reference
+
+	end := linkLabelEnd(text, 0)
+
+	if end < 0 || end+1 >= len(text) || text[end+1] != ':' {
+		return "", ref, "", false
+	}
+
+	label := normalizeLabel(text[1:end])
+
+	if label == "" {
+		return "", ref, "", false
+	}
+
+	start := skipWhitespace(text, end+2, true)
+	destination, pos, ok := parseLinkDestination(text, start)
+
+	// only destinations in angle brackets can be empty
+	if !ok || pos == start {
+		return "", ref, "", false
+	}
+
+	ref.destination = destination
+
+	// the title has to be separated by whitespace
+	titleStart := skipWhitespace(text, pos, true)
+
+	if titleStart > pos {
+		if title, titleEnd, ok := parseLinkTitle(text, titleStart); ok {
+			if lineEnd, ok := restOfLineBlank(text, titleEnd); ok {
+				ref.title = title
+				return label, ref, text[lineEnd:], true
+			}
+		}
+	}
+
+	// without a title, the destination has to end the line
+	if lineEnd, ok := restOfLineBlank(text, pos); ok {
+		return label, ref, text[lineEnd:], true
+	}
+
+	return "", ref, "", false
+}
+
+func restOfLineBlank(text string, pos int) (int, bool) {
+
+	for pos < len(text) && (text[pos] == ' ' || text[pos] == '\t') {
+		pos++
+	}
+
+	if pos == len(text) {
+		return pos, true
+	}
+
+	if text[pos] == '\n' {
+		return pos + 1, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,721 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package markdown
+
+import (
+	"github.com/gospel-sh/gospel"
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	tagName     = `[A-Za-z][A-Za-z0-9-]*`
+	attribute   = `(?:\s+[a-zA-Z_:][a-zA-Z0-9_.:-]*(?:\s*=\s*(?:[^"'=<>` + "`" + `\x00-\x20]+|'[^']*'|"[^"]*"))?)`
+	openTag     = `<` + tagName + attribute + `*\s*/?>`
+	closingTag  = `</` + tagName + `\s*>`
+	htmlComment = `<!-->|<!--->|<!--[\s\S]*?-->`
+	htmlPI      = `<\?[\s\S]*?\?>`
+	declaration = `<![A-Za-z][^>]*>`
+	cdata       = `<!\[CDATA\[[\s\S]*?\]\]>`
+)
+
+var (
+	inlineHTMLRegexp    = regexp.MustCompile(`^(?:` + openTag + `|` + closingTag + `|` + htmlComment + `|` + htmlPI + `|` + declaration + `|` + cdata + `)`)
+	entityRegexp        = regexp.MustCompile(`^&(?:#[xX][0-9a-fA-F]{1,6}|#[0-9]{1,7}|[a-zA-Z][a-zA-Z0-9]{1,31});`)
+	uriAutolinkRegexp   = regexp.MustCompile(`^<([a-zA-Z][a-zA-Z0-9+.\-]{1,31}:[^<>\x00-\x20]*)>`)
+	emailAutolinkRegexp = regexp.MustCompile(`^<([a-zA-Z0-9.!#$%&'*+/=?^_` + "`" + `{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*)>`)
+)
+
+// A node of inline content, either text, an element or a delimiter run
+// that might become emphasis
+type node struct {
+	text    string
+	element *gospel.HTMLElement
+	// the plain text of the element (e.g. for image descriptions)
+	plain string
+	// delimiter runs of '*', '_' or '~'
+	delim     byte
+	count     int
+	origCount int
+	canOpen   bool
+	canClose  bool
+	// opening brackets of links and images
+	bracket bool
+}
+
+type bracket struct {
+	node   *node
+	image  bool
+	active bool
+	// the position after the bracket
+	pos int
+}
+
+type inlineParser struct {
+	renderer   *Renderer
+	references map[string]reference
+	source     string
+	pos        int
+	nodes      []*node
+	brackets   []*bracket
+}
+
+func isASCIIPunctuation(c byte) bool {
+	return c < utf8.RuneSelf && strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
+
+func isPunctuation(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
+
+func skipWhitespace(text string, pos int, newlines bool) int {
+	for pos < len(text) && (text[pos] == ' ' || text[pos] == '\t' || newlines && text[pos] == '\n') {
+		pos++
+	}
+	return pos
+}
+
+// Replaces backslash escapes and entities
+func unescape(text string) string {
+
+	if !strings.ContainsAny(text, "\\&") {
+		return text
+	}
+
+	var b strings.Builder
+
+	for i := 0; i < len(text); i++ {
+		switch {
+		case text[i] == '\\' && i+1 < len(text) && isASCIIPunctuation(text[i+1]):
+			b.WriteByte(text[i+1])
+			i++
+		case text[i] == '&':
+			if entity := entityRegexp.FindString(text[i:]); entity != "" {
+				b.WriteString(html.UnescapeString(entity))
+				i += len(entity) - 1
+			} else {
+				b.WriteByte('&')
+			}
+		default:
+			b.WriteByte(text[i])
+		}
+	}
+
+	return b.String()
+}
+
+// Returns the position of the closing bracket of the link label starting
+// at the given position, or -1 if there's no valid label
+func linkLabelEnd(text string, start int) int {
+
+	if start >= len(text) || text[start] != '[' {
+		return -1
+	}
+
+	for i := start + 1; i < len(text) && i-start <= 1000; i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '[':
+			return -1
+		case ']':
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Labels are matched case-insensitively and with collapsed whitespace
+func normalizeLabel(label string) string {
+	return strings.ToLower(strings.ToUpper(strings.Join(strings.Fields(label), " ")))
+}
+
+func parseLinkDestination(text string, pos int) (string, int, bool) {
+
+	if pos < len(text) && text[pos] == '<' {
+		for i := pos + 1; i < len(text); i++ {
+			switch text[i] {
+			case '\\':
+				i++
+			case '\n', '<':
+				return "", 0, false
+			case '>':
+				return unescape(text[pos+1 : i]), i + 1, true
+			}
+		}
+		return "", 0, false
+	}
+
+	depth := 0
+	i := pos
+
+loop:
+	for ; i < len(text); i++ {
+		switch c := text[i]; {
+		case c == '\\' && i+1 < len(text) && isASCIIPunctuation(text[i+1]):
+			i++
+		case c == '(':
+			depth++
+		case c == ')':
+			if depth == 0 {
+				break loop
+			}
+			depth--
+		case c <= ' ' || c == 0x7f:
+			break loop
+		}
+	}
+
+	if depth != 0 {
+		return "", 0, false
+	}
+
+	return unescape(text[pos:i]), i, true
+}
+
+func parseLinkTitle(text string, pos int) (string, int, bool) {
+
+	if pos >= len(text) {
+		return "", 0, false
+	}
+
+	closing := text[pos]
+
+	switch closing {
+	case '"', '\'':
+	case '(':
+		closing = ')'
+	default:
+		return "", 0, false
+	}
+
+	for i := pos + 1; i < len(text); i++ {
+		switch {
+		case text[i] == '\\':
+			i++
+		case text[i] == closing:
+			return unescape(text[pos+1 : i]), i + 1, true
+		case closing == ')' && text[i] == '(':
+			return "", 0, false
+		}
+	}
+
+	return "", 0, false
+}
+
+func isText(n *node) bool {
+	return n.element == nil && n.delim == 0 && !n.bracket
+}
+
+func (p *inlineParser) addText(text string) {
+
+	if text == "" {
+		return
+	}
+
+	if n := len(p.nodes); n > 0 && isText(p.nodes[n-1]) {
+		p.nodes[n-1].text += text
+		return
+	}
+
+	p.nodes = append(p.nodes, &node{text: text})
+}
+
+func (p *inlineParser) addElement(element *gospel.HTMLElement, plain string) {
+	p.nodes = append(p.nodes, &node{element: element, plain: plain})
+}
+
+func (p *inlineParser) parse() {
+
+	src := p.source
+
+	for p.pos < len(src) {
+
+		c := src[p.pos]
+
+		switch c {
+		case '\\':
+			if p.pos+1 < len(src) && src[p.pos+1] == '\n' {
+				p.pos += 2
+				p.lineBreak(true)
+			} else if p.pos+1 < len(src) && isASCIIPunctuation(src[p.pos+1]) {
+				p.addText(src[p.pos+1 : p.pos+2])
+				p.pos += 2
+			} else {
+				p.addText("\\")
+				p.pos++
+			}
+		case '`':
+			p.codeSpan()
+		case '*', '_':
+			p.delimiterRun(c)
+		case '~':
+			if p.renderer.Strikethrough {
+				p.delimiterRun(c)
+			} else {
+				p.addText("~")
+				p.pos++
+			}
+		case '[':
+			p.nodes = append(p.nodes, &node{text: "[", bracket: true})
+			p.brackets = append(p.brackets, &bracket{node: p.nodes[len(p.nodes)-1], active: true, pos: p.pos + 1})
+			p.pos++
+		case '!':
+			if p.pos+1 < len(src) && src[p.pos+1] == '[' {
+				p.nodes = append(p.nodes, &node{text: "![", bracket: true})
+				p.brackets = append(p.brackets, &bracket{node: p.nodes[len(p.nodes)-1], image: true, active: true, pos: p.pos + 2})
+				p.pos += 2
+			} else {
+				p.addText("!")
+				p.pos++
+			}
+		case ']':
+			p.closeBracket()
+		case '<':
+			p.angleBracket()
+		case '&':
+			if entity := entityRegexp.FindString(src[p.pos:]); entity != "" {
+				p.addText(html.UnescapeString(entity))
+				p.pos += len(entity)
+			} else {
+				p.addText("&")
+				p.pos++
+			}
+		case '\n':
+			p.pos++
+			p.lineBreak(false)
+		default:
+			end := p.pos + 1
+			for end < len(src) && strings.IndexByte("\\`*_~[]!<&\n", src[end]) < 0 {
+				end++
+			}
+			p.addText(src[p.pos:end])
+			p.pos = end
+		}
+	}
+}
+
+func (p *inlineParser) lineBreak(hard bool) {
+
+	if n := len(p.nodes); n > 0 && isText(p.nodes[n-1]) {
+		last := p.nodes[n-1]
+		trimmed := strings.TrimRight(last.text, " ")
+		// two or more spaces at the end of a line make a hard break
+		if len(last.text)-len(trimmed) >= 2 {
+			hard = true
+		}
+		last.text = trimmed
+	}
+
+	if hard {
+		p.addElement(gospel.Br(), "\n")
+	} else {
+		p.addText("\n")
+	}
+
+	// we skip leading spaces on the next line
+	p.pos = skipWhitespace(p.source, p.pos, false)
+}
+
+func (p *inlineParser) codeSpan() {
+
+	src := p.source
+	start := p.pos
+
+	for p.pos < len(src) && src[p.pos] == '`' {
+		p.pos++
+	}
+
+	n := p.pos - start
+
+	for i := p.pos; i < len(src); {
+
+		if src[i] != '`' {
+			i++
+			continue
+		}
+
+		j := i
+
+		for j < len(src) && src[j] == '`' {
+			j++
+		}
+
+		if j-i == n {
+			content := strings.ReplaceAll(src[p.pos:i], "\n", " ")
+			if len(content) >= 2 && content[0] == ' ' && content[len(content)-1] == ' ' && strings.Trim(content, " ") != "" {
+				content = content[1 : len(content)-1]
+			}
+			p.addElement(gospel.Code(content), content)
+			p.pos = j
+			return
+		}
+
+		i = j
+	}
+
+	// there's no matching backtick string
+	p.addText(src[start:p.pos])
+}
+
+func (p *inlineParser) delimiterRun(c byte) {
+
+	src := p.source
+	start := p.pos
+
+	for p.pos < len(src) && src[p.pos] == c {
+		p.pos++
+	}
+
+	n := p.pos - start
+
+	if c == '~' && n > 2 {
+		p.addText(src[start:p.pos])
+		return
+	}
+
+	before, after := '\n', '\n'
+
+	if start > 0 {
+		before, _ = utf8.DecodeLastRuneInString(src[:start])
+	}
+
+	if p.pos < len(src) {
+		after, _ = utf8.DecodeRuneInString(src[p.pos:])
+	}
+
+	leftFlanking := !unicode.IsSpace(after) && (!isPunctuation(after) || unicode.IsSpace(before) || isPunctuation(before))
+	rightFlanking := !unicode.IsSpace(before) && (!isPunctuation(before) || unicode.IsSpace(after) || isPunctuation(after))
+
+	d := &node{
+		text:      src[start:p.pos],
+		delim:     c,
+		count:     n,
+		origCount: n,
+		canOpen:   leftFlanking,
+		canClose:  rightFlanking,
+	}
+
+	// intraword emphasis isn't possible with underscores
+	if c == '_' {
+		d.canOpen = leftFlanking && (!rightFlanking || isPunctuation(before))
+		d.canClose = rightFlanking && (!leftFlanking || isPunctuation(after))
+	}
+
+	p.nodes = append(p.nodes, d)
+}
+
+func (p *inlineParser) angleBracket() {
+
+	rest := p.source[p.pos:]
+
+	if match := uriAutolinkRegexp.FindStringSubmatch(rest); match != nil {
+		p.addElement(p.renderer.link(normalizeURL(match[1]), "", []any{match[1]}), match[1])
+		p.pos += len(match[0])
+		return
+	}
+
+	if match := emailAutolinkRegexp.FindStringSubmatch(rest); match != nil {
+		p.addElement(p.renderer.link("mailto:"+normalizeURL(match[1]), "", []any{match[1]}), match[1])
+		p.pos += len(match[0])
+		return
+	}
+
+	if match := inlineHTMLRegexp.FindString(rest); match != "" {
+		p.addElement(p.renderer.html(match), "")
+		p.pos += len(match)
+		return
+	}
+
+	p.addText("<")
+	p.pos++
+}
+
+func (p *inlineParser) index(n *node) int {
+	for i, other := range p.nodes {
+		if other == n {
+			return i
+		}
+	}
+	return -1
+}
+
+// Tries to parse an inline link or a reference after a closing bracket
+func (p *inlineParser) linkTarget(opener *bracket) (string, string, int, bool) {
+
+	src := p.source
+	pos := p.pos + 1
+
+	if pos < len(src) && src[pos] == '(' {
+
+		i := skipWhitespace(src, pos+1, true)
+
+		if destination, end, ok := parseLinkDestination(src, i); ok {
+
+			j := skipWhitespace(src, end, true)
+			title := ""
+
+			if j > end && j < len(src) && strings.IndexByte("\"'(", src[j]) >= 0 {
+				if title, j, ok = parseLinkTitle(src, j); ok {
+					j = skipWhitespace(src, j, true)
+				}
+			}
+
+			if ok && j < len(src) && src[j] == ')' {
+				return destination, title, j + 1, true
+			}
+		}
+	}
+
+	// we try to find a reference instead
+	label := src[opener.pos:p.pos]
+	end := pos
+
+	if labelEnd := linkLabelEnd(src, pos); labelEnd > pos+1 {
+		// a full reference
+		label = src[pos+1 : labelEnd]
+		end = labelEnd + 1
+	} else {
+		if labelEnd == pos+1 {
+			// a collapsed reference
+			end = labelEnd + 1
+		}
+		// the link text has to be a valid label
+		if linkLabelEnd(src, opener.pos-1) != p.pos {
+			return "", "", 0, false
+		}
+	}
+
+	if ref, ok := p.references[normalizeLabel(label)]; ok && strings.TrimSpace(label) != "" {
+		return ref.destination, ref.title, end, true
+	}
+
+	return "", "", 0, false
+}
+
+func (p *inlineParser) closeBracket() {
+
+	if len(p.brackets) == 0 {
+		p.addText("]")
+		p.pos++
+		return
+	}
+
+	opener := p.brackets[len(p.brackets)-1]
+	p.brackets = p.brackets[:len(p.brackets)-1]
+
+	if !opener.active {
+		p.addText("]")
+		p.pos++
+		return
+	}
+
+	destination, title, end, ok := p.linkTarget(opener)
+
+	if !ok {
+		p.addText("]")
+		p.pos++
+		return
+	}
+
+	i := p.index(opener.node)
+
+	p.processEmphasis(i + 1)
+
+	content := p.nodes[i+1:]
+	plain := plainText(content)
+
+	var element *gospel.HTMLElement
+
+	if opener.image {
+		element = p.renderer.image(normalizeURL(destination), plain, title)
+	} else {
+		element = p.renderer.link(normalizeURL(destination), title, output(content))
+		// links can't contain other links
+		for _, other := range p.brackets {
+			if !other.image {
+				other.active = false
+			}
+		}
+	}
+
+	p.nodes = append(p.nodes[:i], &node{element: element, plain: plain})
+	p.pos = end
+}
+
+// Matches delimiter runs and turns them into emphasis, strong emphasis or
+// strikethrough elements, according to the CommonMark algorithm
+func (p *inlineParser) processEmphasis(bottom int) {
+
+	c := bottom
+
+	for c < len(p.nodes) {
+
+		closer := p.nodes[c]
+
+		if closer.delim == 0 || !closer.canClose || closer.count == 0 {
+			c++
+			continue
+		}
+
+		o := -1
+
+		for k := c - 1; k >= bottom; k-- {
+
+			opener := p.nodes[k]
+
+			if opener.delim != closer.delim || !opener.canOpen || opener.count == 0 {
+				continue
+			}
+
+			if closer.delim == '~' {
+				if opener.count != closer.count {
+					continue
+				}
+			} else if (opener.canClose || closer.canOpen) && (opener.origCount+closer.origCount)%3 == 0 && (opener.origCount%3 != 0 || closer.origCount%3 != 0) {
+				// the "rule of 3"
+				continue
+			}
+
+			o = k
+			break
+		}
+
+		if o < 0 {
+			c++
+			continue
+		}
+
+		opener := p.nodes[o]
+		n := 1
+		tag := gospel.EmTag
+
+		switch {
+		case closer.delim == '~':
+			n = closer.count
+			tag = gospel.Del
+		case opener.count >= 2 && closer.count >= 2:
+			n = 2
+			tag = gospel.Strong
+		}
+
+		content := p.nodes[o+1 : c]
+		element := &node{element: tag(output(content)...), plain: plainText(content)}
+
+		opener.count -= n
+		closer.count -= n
+
+		nodes := make([]*node, 0, len(p.nodes))
+		nodes = append(nodes, p.nodes[:o+1]...)
+		nodes = append(nodes, element)
+		nodes = append(nodes, p.nodes[c:]...)
+
+		p.nodes = nodes
+		c = o + 2
+
+		if opener.count == 0 {
+			p.nodes = append(p.nodes[:o], p.nodes[o+1:]...)
+			c--
+		}
+
+		if closer.count == 0 {
+			p.nodes = append(p.nodes[:c], p.nodes[c+1:]...)
+		}
+	}
+}
+
+func delimiterText(n *node) string {
+	return strings.Repeat(string(n.delim), n.count)
+}
+
+// Returns the children of an element for the given nodes
+func output(nodes []*node) []any {
+
+	children := make([]any, 0, len(nodes))
+	text := ""
+
+	for _, n := range nodes {
+		switch {
+		case n.element != nil:
+			if text != "" {
+				children = append(children, text)
+				text = ""
+			}
+			children = append(children, n.element)
+		case n.delim != 0:
+			text += delimiterText(n)
+		default:
+			text += n.text
+		}
+	}
+
+	if text != "" {
+		children = append(children, text)
+	}
+
+	return children
+}
+
+func plainText(nodes []*node) string {
+
+	var b strings.Builder
+
+	for _, n := range nodes {
+		switch {
+		case n.element != nil:
+			b.WriteString(n.plain)
+		case n.delim != 0:
+			b.WriteString(delimiterText(n))
+		default:
+			b.WriteString(n.text)
+		}
+	}
+
+	return b.String()
+}
+
+// Percent-encodes characters that aren't allowed in URLs
+func normalizeURL(url string) string {
+
+	var b strings.Builder
+
+	for i := 0; i < len(url); i++ {
+		c := url[i]
+		switch {
+		case c == '%' && i+2 < len(url) && isHex(url[i+1]) && isHex(url[i+2]):
+			b.WriteByte(c)
+		case c < utf8.RuneSelf && (c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.IndexByte(";/?:@&=+$,-_.!~*'()#", c) >= 0):
+			b.WriteByte(c)
+		default:
+			b.WriteString("%")
+			b.WriteString(strings.ToUpper(hexByte(c)))
+		}
+	}
+
+	return b.String()
+}
+
+func isHex(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+func hexByte(c byte) string {
+	const digits = "0123456789abcdef"
+	return string([]byte{digits[c>>4], digits[c&0xf]})
+}
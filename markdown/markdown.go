@@ -0,0 +1,278 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+// Renders Markdown (CommonMark with the GitHub extensions for tables, task
+// lists and strikethrough) to gospel elements, so that styles, decorators
+// and tree walking work for Markdown content like for any other element.
+package markdown
+
+import (
+	"fmt"
+	"github.com/gospel-sh/gospel"
+	"strings"
+)
+
+// Hooks override how specific elements are rendered. Each hook is optional.
+type Hooks struct {
+	// text is the plain text of the heading, e.g. to generate an anchor
+	Heading   func(level int, text string, children []any) *gospel.HTMLElement
+	Link      func(href, title string, children []any) *gospel.HTMLElement
+	Image     func(src, alt, title string) *gospel.HTMLElement
+	CodeBlock func(language, code string) *gospel.HTMLElement
+	// called for raw HTML blocks and inline HTML
+	HTML func(html string) *gospel.HTMLElement
+}
+
+type Renderer struct {
+	Hooks
+	// Raw HTML is escaped unless this is set. Only enable it for trusted
+	// content, or sanitize the HTML via the HTML hook instead.
+	AllowHTML bool
+	// GitHub extensions
+	Tables        bool
+	TaskLists     bool
+	Strikethrough bool
+}
+
+// Returns a renderer with all GitHub extensions enabled
+func MakeRenderer() *Renderer {
+	return &Renderer{
+		Tables:        true,
+		TaskLists:     true,
+		Strikethrough: true,
+	}
+}
+
+// Renders Markdown with the default renderer
+func Render(source string) *gospel.HTMLElement {
+	return MakeRenderer().Render(source)
+}
+
+// Renders Markdown to a fragment containing the top-level elements
+func (r *Renderer) Render(source string) *gospel.HTMLElement {
+
+	p := &blockParser{
+		references: map[string]reference{},
+		tables:     r.Tables,
+	}
+
+	blocks := p.parse(splitLines(source))
+
+	return gospel.F(r.blocks(blocks, p.references, false))
+}
+
+func (r *Renderer) inlines(text string, references map[string]reference) []*node {
+
+	p := &inlineParser{
+		renderer:   r,
+		references: references,
+		source:     text,
+	}
+
+	p.parse()
+	p.processEmphasis(0)
+
+	return p.nodes
+}
+
+func (r *Renderer) blocks(blocks []*block, references map[string]reference, tight bool) []any {
+
+	elements := make([]any, 0, len(blocks))
+
+	for _, b := range blocks {
+
+		switch b.kind {
+		case paragraphBlock:
+			children := output(r.inlines(b.text, references))
+			// paragraphs in tight lists aren't wrapped in <p> tags
+			if tight {
+				elements = append(elements, children...)
+			} else {
+				elements = append(elements, gospel.P(children))
+			}
+		case headingBlock:
+			nodes := r.inlines(b.text, references)
+			elements = append(elements, r.heading(b.level, plainText(nodes), output(nodes)))
+		case thematicBreakBlock:
+			elements = append(elements, gospel.Hr())
+		case codeBlock:
+			language := ""
+			if fields := strings.Fields(unescape(b.info)); len(fields) > 0 {
+				language = fields[0]
+			}
+			elements = append(elements, r.codeBlock(language, b.text))
+		case htmlBlock:
+			elements = append(elements, r.html(b.text))
+		case blockquoteBlock:
+			elements = append(elements, gospel.Blockquote(r.blocks(b.children, references, false)))
+		case listBlock:
+			elements = append(elements, r.list(b, references))
+		case tableBlock:
+			elements = append(elements, r.table(b, references))
+		}
+	}
+
+	return elements
+}
+
+func (r *Renderer) list(list *block, references map[string]reference) *gospel.HTMLElement {
+
+	items := make([]any, 0, len(list.children))
+
+	for _, item := range list.children {
+
+		args := []any{}
+		children := item.children
+
+		if r.TaskLists && len(children) > 0 && children[0].kind == paragraphBlock {
+			if checked, text, ok := taskMarker(children[0].text); ok {
+				checkbox := []any{gospel.Type("checkbox"), gospel.DisabledAttr()}
+				if checked {
+					checkbox = append(checkbox, gospel.CheckedAttr())
+				}
+				args = append(args, gospel.Class("task-list-item"), gospel.Input(checkbox...), " ")
+				// we don't modify the block, as it belongs to the parsed tree
+				first := *children[0]
+				first.text = text
+				children = append([]*block{&first}, children[1:]...)
+			}
+		}
+
+		args = append(args, r.blocks(children, references, list.tight))
+		items = append(items, gospel.Li(args...))
+	}
+
+	if !list.ordered {
+		return gospel.Ul(items)
+	}
+
+	if list.start != 1 {
+		return gospel.Ol(gospel.Start(fmt.Sprint(list.start)), items)
+	}
+
+	return gospel.Ol(items)
+}
+
+// Returns whether a list item starts with a task marker like "[x]"
+func taskMarker(text string) (bool, string, bool) {
+
+	if len(text) < 4 || text[0] != '[' || text[2] != ']' || (text[3] != ' ' && text[3] != '\n') {
+		return false, "", false
+	}
+
+	switch text[1] {
+	case ' ':
+		return false, strings.TrimLeft(text[4:], " "), true
+	case 'x', 'X':
+		return true, strings.TrimLeft(text[4:], " "), true
+	}
+
+	return false, "", false
+}
+
+func (r *Renderer) table(table *block, references map[string]reference) *gospel.HTMLElement {
+
+	cell := func(tag func(...any) *gospel.HTMLElement, text string, column int) *gospel.HTMLElement {
+		args := []any{}
+		if align := table.align[column]; align != "" {
+			args = append(args, gospel.Style("text-align: "+align))
+		}
+		return tag(append(args, output(r.inlines(text, references)))...)
+	}
+
+	row := func(tag func(...any) *gospel.HTMLElement, cells []string) *gospel.HTMLElement {
+		children := make([]any, len(cells))
+		for i, text := range cells {
+			children[i] = cell(tag, text, i)
+		}
+		return gospel.Tr(children)
+	}
+
+	args := []any{gospel.Thead(row(gospel.Th, table.rows[0]))}
+
+	if len(table.rows) > 1 {
+		rows := make([]any, 0, len(table.rows)-1)
+		for _, cells := range table.rows[1:] {
+			rows = append(rows, row(gospel.Td, cells))
+		}
+		args = append(args, gospel.Tbody(rows))
+	}
+
+	return gospel.Table(args...)
+}
+
+func (r *Renderer) heading(level int, text string, children []any) *gospel.HTMLElement {
+
+	if r.Heading != nil {
+		return r.Heading(level, text, children)
+	}
+
+	return []func(...any) *gospel.HTMLElement{gospel.H1, gospel.H2, gospel.H3, gospel.H4, gospel.H5, gospel.H6}[level-1](children)
+}
+
+func (r *Renderer) link(href, title string, children []any) *gospel.HTMLElement {
+
+	if r.Link != nil {
+		return r.Link(href, title, children)
+	}
+
+	args := []any{gospel.Href(href)}
+
+	if title != "" {
+		args = append(args, gospel.TitleAttr(title))
+	}
+
+	return gospel.A(append(args, children)...)
+}
+
+func (r *Renderer) image(src, alt, title string) *gospel.HTMLElement {
+
+	if r.Image != nil {
+		return r.Image(src, alt, title)
+	}
+
+	args := []any{gospel.Src(src), gospel.Alt(alt)}
+
+	if title != "" {
+		args = append(args, gospel.TitleAttr(title))
+	}
+
+	return gospel.Img(args...)
+}
+
+func (r *Renderer) codeBlock(language, code string) *gospel.HTMLElement {
+
+	if r.CodeBlock != nil {
+		return r.CodeBlock(language, code)
+	}
+
+	if language != "" {
+		return gospel.Pre(gospel.Code(gospel.Class("language-"+language), code))
+	}
+
+	return gospel.Pre(gospel.Code(code))
+}
+
+func (r *Renderer) html(html string) *gospel.HTMLElement {
+
+	if r.HTML != nil {
+		return r.HTML(html)
+	}
+
+	if r.AllowHTML {
+		return gospel.SafeLiteral(html)
+	}
+
+	return gospel.Literal(html)
+}
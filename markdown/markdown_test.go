@@ -0,0 +1,135 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package markdown
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"github.com/gospel-sh/gospel"
+	"testing"
+)
+
+type markdownTest struct {
+	source   string
+	expected string
+}
+
+var markdownTests = []markdownTest{
+	// headings
+	{"# foo", `<h1>foo</h1>`},
+	{"###### foo ###", `<h6>foo</h6>`},
+	{"####### foo", `<p>####### foo</p>`},
+	{"#5 bolt", `<p>#5 bolt</p>`},
+	{"Foo *bar*\n=========", `<h1>Foo <em>bar</em></h1>`},
+	{"Foo\n---\nbar", `<h2>Foo</h2><p>bar</p>`},
+	// thematic breaks
+	{"***\n---\n___", `<hr/><hr/><hr/>`},
+	{" - - -", `<hr/>`},
+	{"Foo\n***\nbar", `<p>Foo</p><hr/><p>bar</p>`},
+	// code
+	{"    a simple\n      indented code block", "<pre><code>a simple\n  indented code block\n</code></pre>"},
+	{"```go\nfunc main() {\n\t<b>\n}\n```", "<pre><code class=\"language-go\">func main() {\n\t&lt;b&gt;\n}\n</code></pre>"},
+	{"\tfoo\tbaz\t\tbim", "<pre><code>foo\tbaz\t\tbim\n</code></pre>"},
+	{"  \tfoo\tbaz", "<pre><code>foo\tbaz\n</code></pre>"},
+	{"-\tfoo\n\n\tbar", "<ul><li><p>foo</p><p>bar</p></li></ul>"},
+	{">\tfoo", "<blockquote><p>foo</p></blockquote>"},
+	{"~~~\naaa\n```\n~~~", "<pre><code>aaa\n```\n</code></pre>"},
+	{"  ```\n  aaa\n    bbb\n  ```", "<pre><code>aaa\n  bbb\n</code></pre>"},
+	{"Foo\n    bar", "<p>Foo\nbar</p>"},
+	// block quotes
+	{"> # Foo\n> bar\nbaz", "<blockquote><h1>Foo</h1><p>bar\nbaz</p></blockquote>"},
+	{"> foo\n\n> bar", "<blockquote><p>foo</p></blockquote><blockquote><p>bar</p></blockquote>"},
+	{"> > nested", "<blockquote><blockquote><p>nested</p></blockquote></blockquote>"},
+	// lists
+	{"- one\n- two\n- three", `<ul><li>one</li><li>two</li><li>three</li></ul>`},
+	{"- one\n\n- two", `<ul><li><p>one</p></li><li><p>two</p></li></ul>`},
+	{"3. three\n4. four", `<ol start="3"><li>three</li><li>four</li></ol>`},
+	{"- foo\n  - bar\n    - baz", `<ul><li>foo<ul><li>bar<ul><li>baz</li></ul></li></ul></li></ul>`},
+	{"- a\n- b\n\n  c\n- d", `<ul><li><p>a</p></li><li><p>b</p><p>c</p></li><li><p>d</p></li></ul>`},
+	{"- foo\n+ bar", `<ul><li>foo</li></ul><ul><li>bar</li></ul>`},
+	{"1. a\n\n   ```\n   code\n   ```", "<ol><li><p>a</p><pre><code>code\n</code></pre></li></ol>"},
+	{"The number of windows in my house is\n14.  The number of doors is 6.", "<p>The number of windows in my house is\n14.  The number of doors is 6.</p>"},
+	{"- a\n > b\n", `<ul><li>a</li></ul><blockquote><p>b</p></blockquote>`},
+	{"- [ ] todo\n- [x] done", `<ul><li class="task-list-item"><input type="checkbox" disabled/> todo</li><li class="task-list-item"><input type="checkbox" disabled checked/> done</li></ul>`},
+	// emphasis
+	{"*foo bar*", `<p><em>foo bar</em></p>`},
+	{"a * foo bar*", `<p>a * foo bar*</p>`},
+	{"foo*bar*", `<p>foo<em>bar</em></p>`},
+	{"foo_bar_", `<p>foo_bar_</p>`},
+	{"**foo bar**", `<p><strong>foo bar</strong></p>`},
+	{"***strong emph***", `<p><em><strong>strong emph</strong></em></p>`},
+	{"*foo**bar**baz*", `<p><em>foo<strong>bar</strong>baz</em></p>`},
+	{"*foo**bar*", `<p><em>foo**bar</em></p>`},
+	{"**foo*", `<p>*<em>foo</em></p>`},
+	{"_foo *bar*_", `<p><em>foo <em>bar</em></em></p>`},
+	{"~~gone~~ and ~this~", `<p><del>gone</del> and <del>this</del></p>`},
+	// code spans, escapes and entities
+	{"`` foo ` bar ``", "<p><code>foo ` bar</code></p>"},
+	{"`foo", "<p>`foo</p>"},
+	{"\\*not emphasized*", `<p>*not emphasized*</p>`},
+	{"&amp; &copy; &#35; &nonsense;", `<p>&amp; © # &amp;nonsense;</p>`},
+	{"foo  \nbar\\\nbaz", `<p>foo<br/>bar<br/>baz</p>`},
+	// links and images
+	{"[link](/uri \"title\")", `<p><a href="/uri" title="title">link</a></p>`},
+	{"[link](</my uri>)", `<p><a href="/my%20uri">link</a></p>`},
+	{"[link](foo(and(bar)))", `<p><a href="foo(and(bar))">link</a></p>`},
+	{"[link *foo **bar** `#`*](/uri)", `<p><a href="/uri">link <em>foo <strong>bar</strong> <code>#</code></em></a></p>`},
+	{"[foo [bar](/uri)](/uri)", `<p>[foo <a href="/uri">bar</a>](/uri)</p>`},
+	{"*[foo*](/uri)", `<p>*<a href="/uri">foo*</a></p>`},
+	{"[foo][bar]\n\n[bar]: /url \"title\"", `<p><a href="/url" title="title">foo</a></p>`},
+	{"[Foo Bar]\n\n[foo bar]: /url", `<p><a href="/url">Foo Bar</a></p>`},
+	{"[foo][]\n\n[foo]: /url", `<p><a href="/url">foo</a></p>`},
+	{"[foo]\n\n[bar]: /url", `<p>[foo]</p>`},
+	{"![foo *bar*](/url \"title\")", `<p><img src="/url" alt="foo bar" title="title"/></p>`},
+	{"<https://example.com/?a=b>", `<p><a href="https://example.com/?a=b">https://example.com/?a=b</a></p>`},
+	{"<foo@bar.example.com>", `<p><a href="mailto:foo@bar.example.com">foo@bar.example.com</a></p>`},
+	{"[xss](javascript:alert(1))", `<p><a href="about:invalid#zGospelz">xss</a></p>`},
+	// raw HTML is escaped by default
+	{"<div>\n*hi*\n</div>", "&lt;div&gt;\n*hi*\n&lt;/div&gt;"},
+	{"a <span class=\"x\">*b*</span>", `<p>a &lt;span class=&#34;x&#34;&gt;<em>b</em>&lt;/span&gt;</p>`},
+	// tables
+	{"| a | b |\n|:--|--:|\n| 1 | `\\|` |\n| 2 |", `<table><thead><tr><th style="text-align: left">a</th><th style="text-align: right">b</th></tr></thead>` +
+		`<tbody><tr><td style="text-align: left">1</td><td style="text-align: right"><code>|</code></td></tr>` +
+		`<tr><td style="text-align: left">2</td><td style="text-align: right"></td></tr></tbody></table>`},
+	{"| a |\n| - |", `<table><thead><tr><th>a</th></tr></thead></table>`},
+}
+
+func TestMarkdown(t *testing.T) {
+	for _, test := range markdownTests {
+		if diff := cmp.Diff(test.expected, Render(test.source).RenderElement()); diff != "" {
+			t.Errorf("unexpected output for %q: %s", test.source, diff)
+		}
+	}
+}
+
+func TestHooks(t *testing.T) {
+
+	renderer := MakeRenderer()
+	renderer.AllowHTML = true
+
+	renderer.Heading = func(level int, text string, children []any) *gospel.HTMLElement {
+		return gospel.H2(gospel.Id(text), children)
+	}
+
+	renderer.CodeBlock = func(language, code string) *gospel.HTMLElement {
+		return gospel.Div(gospel.Class("highlight "+language), code)
+	}
+
+	html := renderer.Render("# Intro\n\n```sh\nls\n```\n\n<b>bold</b>").RenderElement()
+	expected := `<h2 id="Intro">Intro</h2><div class="highlight sh">ls` + "\n" + `</div><p><b>bold</b></p>`
+
+	if diff := cmp.Diff(expected, html); diff != "" {
+		t.Fatalf("unexpected output: %s", diff)
+	}
+}
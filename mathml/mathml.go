@@ -0,0 +1,101 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+// MathML Core elements and attributes. Like the svg package, it is meant
+// to be imported with its name, e.g.
+//
+//	mathml.Math(mathml.Mfrac(mathml.Mn("1"), mathml.Mn("2")))
+package mathml
+
+import (
+	"github.com/gospel-sh/gospel"
+)
+
+func Tag(name string, decorators ...gospel.HTMLElementDecorator) func(args ...any) *gospel.HTMLElement {
+	return gospel.ForeignTag(gospel.MathMLNamespace, name, decorators...)
+}
+
+// MathML Tags
+// https://www.w3.org/TR/mathml-core/#mathml-elements-and-attributes
+
+var Math = Tag("math", gospel.DeclareNamespace())
+
+var Annotation = Tag("annotation")
+var AnnotationXML = Tag("annotation-xml")
+var Maction = Tag("maction")
+var Merror = Tag("merror")
+var Mfrac = Tag("mfrac")
+var Mi = Tag("mi")
+var Mmultiscripts = Tag("mmultiscripts")
+var Mn = Tag("mn")
+var Mo = Tag("mo")
+var Mover = Tag("mover")
+var Mpadded = Tag("mpadded")
+var Mphantom = Tag("mphantom")
+var Mprescripts = Tag("mprescripts")
+var Mroot = Tag("mroot")
+var Mrow = Tag("mrow")
+var Ms = Tag("ms")
+var Mspace = Tag("mspace")
+var Msqrt = Tag("msqrt")
+var Mstyle = Tag("mstyle")
+var Msub = Tag("msub")
+var Msubsup = Tag("msubsup")
+var Msup = Tag("msup")
+var Mtable = Tag("mtable")
+var Mtd = Tag("mtd")
+var Mtext = Tag("mtext")
+var Mtr = Tag("mtr")
+var Munder = Tag("munder")
+var Munderover = Tag("munderover")
+var Semantics = Tag("semantics")
+
+// MathML Attributes
+
+var attrib = gospel.Attrib
+
+var Id = attrib("id")
+var Class = attrib("class")
+var Style = attrib("style")
+var Dir = attrib("dir")
+var Display = attrib("display")
+var Displaystyle = attrib("displaystyle")
+var Scriptlevel = attrib("scriptlevel")
+var Mathvariant = attrib("mathvariant")
+var Mathsize = attrib("mathsize")
+var Mathcolor = attrib("mathcolor")
+var Mathbackground = attrib("mathbackground")
+var Intent = attrib("intent")
+var Arg = attrib("arg")
+var Encoding = attrib("encoding")
+var Linethickness = attrib("linethickness")
+var Form = attrib("form")
+var Fence = attrib("fence")
+var Separator = attrib("separator")
+var Stretchy = attrib("stretchy")
+var Symmetric = attrib("symmetric")
+var Largeop = attrib("largeop")
+var Movablelimits = attrib("movablelimits")
+var Lspace = attrib("lspace")
+var Rspace = attrib("rspace")
+var Minsize = attrib("minsize")
+var Maxsize = attrib("maxsize")
+var Accent = attrib("accent")
+var Accentunder = attrib("accentunder")
+var Width = attrib("width")
+var Height = attrib("height")
+var Depth = attrib("depth")
+var Voffset = attrib("voffset")
+var Columnspan = attrib("columnspan")
+var Rowspan = attrib("rowspan")
@@ -0,0 +1,58 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package mathml_test
+
+import (
+	"github.com/gospel-sh/gospel"
+	. "github.com/gospel-sh/gospel/mathml"
+	"testing"
+)
+
+func TestRendering(t *testing.T) {
+
+	fraction := Mfrac(Mn("1"), Mn("2"))
+
+	formula := gospel.P(
+		Math(
+			Display("block"),
+			fraction,
+			Mspace(Width("1em")),
+			Mrow(Mi("x"), Mo("<"), Mn("2")),
+		),
+	)
+
+	expected := `<p><math display="block" xmlns="http://www.w3.org/1998/Math/MathML">` +
+		`<mfrac><mn>1</mn><mn>2</mn></mfrac><mspace width="1em"/>` +
+		`<mrow><mi>x</mi><mo>&lt;</mo><mn>2</mn></mrow></math></p>`
+
+	if html := formula.RenderElement(); html != expected {
+		t.Fatalf("unexpected output:\n%s\n%s", html, expected)
+	}
+
+	// all elements are foreign elements in the MathML namespace
+	if fraction.Namespace != gospel.MathMLNamespace {
+		t.Fatalf("unexpected namespace: %s", fraction.Namespace)
+	}
+
+	// an explicit namespace declaration isn't duplicated
+	if html := Math(gospel.Attrib("xmlns")(gospel.MathMLNamespace)).RenderElement(); html != `<math xmlns="http://www.w3.org/1998/Math/MathML"/>` {
+		t.Fatalf("unexpected output: %s", html)
+	}
+
+	// empty foreign elements stay self-closing when minified
+	if html := gospel.RenderWith(Math(Mrow(Mi("x"), Mspace())), gospel.MinifiedRenderOptions); html != `<math xmlns=http://www.w3.org/1998/Math/MathML><mrow><mi>x</mi><mspace/></mrow></math>` {
+		t.Fatalf("unexpected minified output: %s", html)
+	}
+}
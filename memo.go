@@ -0,0 +1,167 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package gospel
+
+import (
+	"container/list"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+const DefaultMemoCacheSize = 1024
+
+type memoEntry struct {
+	key   string
+	deps  []any
+	value any
+}
+
+// A size-bounded LRU cache for memoized values, which is shared between
+// requests and therefore safe for concurrent use.
+type MemoCache struct {
+	mutex   sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+func MakeMemoCache(size int) *MemoCache {
+
+	if size <= 0 {
+		size = DefaultMemoCacheSize
+	}
+
+	return &MemoCache{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Returns the cached value if the dependencies are unchanged
+func (m *MemoCache) Get(key string, deps []any) (any, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	element, ok := m.entries[key]
+
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*memoEntry)
+
+	if !reflect.DeepEqual(entry.deps, deps) {
+		// the dependencies have changed, we invalidate the entry
+		m.order.Remove(element)
+		delete(m.entries, key)
+		return nil, false
+	}
+
+	m.order.MoveToFront(element)
+
+	return entry.value, true
+}
+
+func (m *MemoCache) Set(key string, deps []any, value any) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	entry := &memoEntry{
+		key: key,
+		// the caller might modify the slice later on
+		deps:  append([]any(nil), deps...),
+		value: value,
+	}
+
+	if element, ok := m.entries[key]; ok {
+		element.Value = entry
+		m.order.MoveToFront(element)
+		return
+	}
+
+	m.entries[key] = m.order.PushFront(entry)
+
+	// we evict the least recently used entries
+	for m.order.Len() > m.size {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.entries, oldest.Value.(*memoEntry).key)
+	}
+}
+
+func (m *MemoCache) Delete(key string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if element, ok := m.entries[key]; ok {
+		m.order.Remove(element)
+		delete(m.entries, key)
+	}
+}
+
+func (m *MemoCache) Len() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.order.Len()
+}
+
+// Memoizes an element function across requests, re-executing it only if
+// the dependencies change. As the element is reused, it shouldn't contain
+// request-specific state like variables or functions.
+func Memo(c Context, key string, deps []any, elementFunction ElementFunction) Element {
+
+	cacheKey := fmt.Sprintf("%s.%s", c.Key(), key)
+
+	if value, ok := c.MemoCache().Get(cacheKey, deps); ok {
+		if element, ok := value.(Element); ok {
+			return copyMemoElement(element)
+		}
+	}
+
+	element := c.Element(key, elementFunction)
+	// elements are modified while rendering (e.g. by Styled), so all
+	// requests get their own copy of the cached element
+	c.MemoCache().Set(cacheKey, deps, copyMemoElement(element))
+
+	return element
+}
+
+func copyMemoElement(element Element) Element {
+	if htmlElement, ok := element.(*HTMLElement); ok && htmlElement != nil {
+		return htmlElement.DeepCopy()
+	}
+	return element
+}
+
+// Memoizes a value across requests, recomputing it only if the dependencies
+// change. Like variables, memoized values are identified by the order in
+// which they are defined within an element.
+func UseMemo[T any](c Context, deps []any, value func() T) T {
+
+	cacheKey := fmt.Sprintf("%s.memo.%d", c.Key(), c.NextIndex(c.Key()+".memo"))
+
+	if cachedValue, ok := c.MemoCache().Get(cacheKey, deps); ok {
+		if vt, ok := cachedValue.(T); ok {
+			return vt
+		}
+	}
+
+	v := value()
+	c.MemoCache().Set(cacheKey, deps, v)
+
+	return v
+}
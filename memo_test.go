@@ -0,0 +1,138 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package gospel
+
+import (
+	"testing"
+)
+
+func TestMemoCache(t *testing.T) {
+
+	m := MakeMemoCache(2)
+
+	m.Set("a", []any{1}, "a")
+	m.Set("b", []any{1}, "b")
+
+	// a is now the most recently used entry
+	if _, ok := m.Get("a", []any{1}); !ok {
+		t.Fatalf("expected a to be cached")
+	}
+
+	m.Set("c", []any{1}, "c")
+
+	if _, ok := m.Get("b", []any{1}); ok {
+		t.Fatalf("expected b to be evicted")
+	}
+
+	if value, ok := m.Get("a", []any{1}); !ok || value != "a" {
+		t.Fatalf("expected a to be cached")
+	}
+
+	if m.Len() != 2 {
+		t.Fatalf("expected two entries, got %d", m.Len())
+	}
+
+	// changed dependencies invalidate the entry
+	if _, ok := m.Get("c", []any{2}); ok {
+		t.Fatalf("expected a miss for changed dependencies")
+	}
+
+	if _, ok := m.Get("c", []any{1}); ok || m.Len() != 1 {
+		t.Fatalf("expected the entry to be removed")
+	}
+
+	// the dependencies are copied
+	deps := []any{"x"}
+	m.Set("d", deps, "d")
+	deps[0] = "y"
+
+	if _, ok := m.Get("d", []any{"x"}); !ok {
+		t.Fatalf("expected the original dependencies")
+	}
+}
+
+func TestMemo(t *testing.T) {
+
+	cache := MakeMemoCache(0)
+	executions := 0
+
+	render := func(deps ...any) *HTMLElement {
+		c := makeTestContext()
+		c.Store.Memo = cache
+		return c.Execute(func(c Context) Element {
+			return Memo(c, "list", deps, func(c Context) Element {
+				executions++
+				return Ul(Li("a"), Li("b"))
+			})
+		}).(*HTMLElement)
+	}
+
+	first := render(1)
+	// rendering might modify the element, which mustn't change the cache
+	first.Attributes = append(first.Attributes, Class("first"))
+	first.Children[0].(*HTMLElement).Attributes = append(first.Children[0].(*HTMLElement).Attributes, Class("item"))
+
+	second := render(1)
+
+	if executions != 1 {
+		t.Fatalf("expected one execution, got %d", executions)
+	}
+
+	if html := second.RenderElement(); html != "<ul><li>a</li><li>b</li></ul>" {
+		t.Fatalf("unexpected output: %s", html)
+	}
+
+	if second == first || second.Children[0] == first.Children[0] {
+		t.Fatalf("expected a copy of the element")
+	}
+
+	render(2)
+
+	if executions != 2 {
+		t.Fatalf("expected the element to be executed again")
+	}
+}
+
+func TestUseMemo(t *testing.T) {
+
+	cache := MakeMemoCache(0)
+	executions := 0
+
+	render := func() (a, b string) {
+		c := makeTestContext()
+		c.Store.Memo = cache
+		c.Execute(func(c Context) Element {
+			// values are identified by their order within the element
+			a = UseMemo(c, []any{1}, func() string { executions++; return "a" })
+			b = UseMemo(c, []any{1}, func() string { executions++; return "b" })
+			return nil
+		})
+		return a, b
+	}
+
+	for i := 0; i < 2; i++ {
+		if a, b := render(); a != "a" || b != "b" {
+			t.Fatalf("expected separate values, got '%s' and '%s'", a, b)
+		}
+	}
+
+	if executions != 2 {
+		t.Fatalf("expected two executions, got %d", executions)
+	}
+
+	if cache.Len() != 2 {
+		t.Fatalf("expected two entries, got %d", cache.Len())
+	}
+}
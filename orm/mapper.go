@@ -14,14 +14,22 @@
 
 package orm
 
+import (
+	"context"
+)
+
 type Mapper[T QueryModel] struct {
 	db func() DB
 }
 
 func (m *Mapper[T]) Objects(filters map[string]any) ([]T, error) {
+	return m.ObjectsContext(context.Background(), filters)
+}
+
+func (m *Mapper[T]) ObjectsContext(ctx context.Context, filters map[string]any) ([]T, error) {
 
 	obj := InitType[T](m.db)
-	objs, err := Load(obj, filters, false)
+	objs, err := LoadContext(ctx, obj, filters, false)
 
 	if err != nil {
 		return nil, err
@@ -46,8 +54,15 @@ func Objects[G any, T interface {
 	*G
 	QueryModel
 }](db func() DB, filters map[string]any) ([]T, error) {
+	return ObjectsContext[G, T](context.Background(), db, filters)
+}
+
+func ObjectsContext[G any, T interface {
+	*G
+	QueryModel
+}](ctx context.Context, db func() DB, filters map[string]any) ([]T, error) {
 	obj := InitType[T](db)
-	objs, err := Load(obj, filters, false)
+	objs, err := LoadContext(ctx, obj, filters, false)
 	if err != nil {
 		return nil, err
 	}
@@ -65,8 +80,15 @@ func Query[G any, T interface {
 	*G
 	QueryModel
 }](db func() DB, query string, args ...any) ([]T, error) {
+	return QueryContext[G, T](context.Background(), db, query, args...)
+}
+
+func QueryContext[G any, T interface {
+	*G
+	QueryModel
+}](ctx context.Context, db func() DB, query string, args ...any) ([]T, error) {
 	obj := InitType[T](db)
-	objs, err := GetQueryStmt(obj, query).Execute(args...)
+	objs, err := GetQueryStmt(obj, query).ExecuteContext(ctx, args...)
 
 	if err != nil {
 		return nil, err
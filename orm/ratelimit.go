@@ -0,0 +1,94 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package orm
+
+import (
+	"fmt"
+	"github.com/gospel-sh/gospel"
+	"time"
+)
+
+// Stores rate limit buckets in a (PostgreSQL) table, so that limits are
+// shared between all instances of an app
+type RateLimitStore struct {
+	db    DB
+	table string
+}
+
+func MakeRateLimitStore(db DB, table string) *RateLimitStore {
+	return &RateLimitStore{
+		db:    db,
+		table: table,
+	}
+}
+
+// Creates the table for the buckets if it doesn't exist yet
+func (r *RateLimitStore) Init() error {
+	_, err := r.db.Exec(fmt.Sprintf(`
+	CREATE TABLE IF NOT EXISTS %s (
+		key TEXT PRIMARY KEY,
+		tokens DOUBLE PRECISION NOT NULL,
+		updated_at TIMESTAMPTZ NOT NULL
+	)`, r.table))
+	return err
+}
+
+func (r *RateLimitStore) Take(key string, limit gospel.RateLimit, now time.Time) (bool, time.Duration, error) {
+
+	tx, err := r.db.Begin()
+
+	if err != nil {
+		return false, 0, err
+	}
+
+	// we roll back if we return before the commit
+	defer tx.Rollback()
+
+	// FOR UPDATE doesn't lock rows that don't exist, so we create the row
+	// first, otherwise concurrent first requests would all get a full bucket
+	if _, err := tx.Exec(fmt.Sprintf(`
+	INSERT INTO %s (key, tokens, updated_at) VALUES ($1, $2, $3)
+	ON CONFLICT (key) DO NOTHING`, r.table), key, float64(limit.Burst), now); err != nil {
+		return false, 0, err
+	}
+
+	var tokens float64
+	var updated time.Time
+
+	// we lock the row so that concurrent requests don't get the same token
+	row := tx.QueryRow(fmt.Sprintf(`SELECT tokens, updated_at FROM %s WHERE key = $1 FOR UPDATE`, r.table), key)
+
+	if err := row.Scan(&tokens, &updated); err != nil {
+		return false, 0, err
+	}
+
+	tokens, allowed, wait := limit.Take(tokens, updated, now)
+
+	if _, err := tx.Exec(fmt.Sprintf(`UPDATE %s SET tokens = $2, updated_at = $3 WHERE key = $1`, r.table), key, tokens, now); err != nil {
+		return false, 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, 0, err
+	}
+
+	return allowed, wait, nil
+}
+
+// Deletes buckets that weren't used for the given duration
+func (r *RateLimitStore) Prune(olderThan time.Duration) error {
+	_, err := r.db.Exec(fmt.Sprintf(`DELETE FROM %s WHERE updated_at < $1`, r.table), time.Now().Add(-olderThan))
+	return err
+}
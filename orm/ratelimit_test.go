@@ -0,0 +1,269 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"github.com/gospel-sh/gospel"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type bucketRow struct {
+	tokens  float64
+	updated time.Time
+}
+
+type rowLock struct {
+	owner    *bucketConn
+	released chan struct{}
+}
+
+// A table of buckets with row locks that are held until the end of the
+// transaction, like in PostgreSQL
+type bucketTable struct {
+	mutex sync.Mutex
+	rows  map[string]*bucketRow
+	locks map[string]*rowLock
+}
+
+// Locks the row with the given key, waiting for other transactions
+func (b *bucketTable) lock(conn *bucketConn, key string) {
+	for {
+		b.mutex.Lock()
+
+		lock, ok := b.locks[key]
+
+		if !ok {
+			b.locks[key] = &rowLock{owner: conn, released: make(chan struct{})}
+			b.mutex.Unlock()
+			return
+		}
+
+		b.mutex.Unlock()
+
+		if lock.owner == conn {
+			return
+		}
+
+		<-lock.released
+	}
+}
+
+func (b *bucketTable) unlock(conn *bucketConn) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for key, lock := range b.locks {
+		if lock.owner == conn {
+			close(lock.released)
+			delete(b.locks, key)
+		}
+	}
+}
+
+func (b *bucketTable) Connect(ctx context.Context) (driver.Conn, error) {
+	return &bucketConn{table: b}, nil
+}
+
+func (b *bucketTable) Driver() driver.Driver {
+	return nil
+}
+
+// Understands the queries of the rate limit store
+type bucketConn struct {
+	table *bucketTable
+}
+
+func (c *bucketConn) Prepare(query string) (driver.Stmt, error) {
+	return &bucketStmt{conn: c, query: strings.Join(strings.Fields(query), " ")}, nil
+}
+
+func (c *bucketConn) Close() error {
+	return nil
+}
+
+func (c *bucketConn) Begin() (driver.Tx, error) {
+	return c, nil
+}
+
+func (c *bucketConn) Commit() error {
+	c.table.unlock(c)
+	return nil
+}
+
+func (c *bucketConn) Rollback() error {
+	c.table.unlock(c)
+	return nil
+}
+
+type bucketStmt struct {
+	conn  *bucketConn
+	query string
+}
+
+func (s *bucketStmt) Close() error {
+	return nil
+}
+
+func (s *bucketStmt) NumInput() int {
+	return -1
+}
+
+func (s *bucketStmt) Exec(args []driver.Value) (driver.Result, error) {
+
+	table := s.conn.table
+	key := args[0].(string)
+
+	switch {
+	case strings.HasPrefix(s.query, "INSERT INTO buckets") && strings.HasSuffix(s.query, "DO NOTHING"):
+		// inserting a row locks it, concurrent inserts wait for the transaction
+		table.lock(s.conn, key)
+	case strings.HasPrefix(s.query, "UPDATE buckets"):
+	default:
+		return nil, fmt.Errorf("unexpected query: %s", s.query)
+	}
+
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+
+	row, ok := table.rows[key]
+
+	if ok && strings.HasPrefix(s.query, "INSERT") {
+		return driver.RowsAffected(0), nil
+	}
+
+	if !ok {
+		row = &bucketRow{}
+		table.rows[key] = row
+	}
+
+	row.tokens, row.updated = args[1].(float64), args[2].(time.Time)
+
+	return driver.RowsAffected(1), nil
+}
+
+func (s *bucketStmt) Query(args []driver.Value) (driver.Rows, error) {
+
+	if !strings.HasPrefix(s.query, "SELECT tokens, updated_at FROM buckets") {
+		return nil, fmt.Errorf("unexpected query: %s", s.query)
+	}
+
+	table := s.conn.table
+	key := args[0].(string)
+
+	table.mutex.Lock()
+	_, ok := table.rows[key]
+	table.mutex.Unlock()
+
+	// FOR UPDATE only locks existing rows
+	if ok {
+		table.lock(s.conn, key)
+	}
+
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+
+	if row, ok := table.rows[key]; ok {
+		return &bucketRows{rows: []*bucketRow{row}}, nil
+	}
+
+	return &bucketRows{}, nil
+}
+
+type bucketRows struct {
+	rows []*bucketRow
+}
+
+func (r *bucketRows) Columns() []string {
+	return []string{"tokens", "updated_at"}
+}
+
+func (r *bucketRows) Close() error {
+	return nil
+}
+
+func (r *bucketRows) Next(dest []driver.Value) error {
+
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+
+	dest[0], dest[1] = r.rows[0].tokens, r.rows[0].updated
+	r.rows = r.rows[1:]
+
+	return nil
+}
+
+func TestRateLimitStore(t *testing.T) {
+
+	table := &bucketTable{
+		rows:  make(map[string]*bucketRow),
+		locks: make(map[string]*rowLock),
+	}
+
+	db := sql.OpenDB(table)
+	defer db.Close()
+
+	store := MakeRateLimitStore(&WrappedDB{DB: db, settings: &DatabaseSettings{}}, "buckets")
+	limit := gospel.PerMinute(1, 3)
+	now := time.Now()
+
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	allowed := 0
+
+	// concurrent first requests mustn't get the same tokens
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ok, _, err := store.Take("key", limit, now)
+
+			if err != nil {
+				t.Error(err)
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			if ok {
+				allowed++
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if allowed != limit.Burst {
+		t.Fatalf("expected %d allowed requests, got %d", limit.Burst, allowed)
+	}
+
+	// the bucket is refilled over time
+	if ok, _, err := store.Take("key", limit, now.Add(time.Minute)); err != nil || !ok {
+		t.Fatalf("expected a refilled bucket: %v", err)
+	}
+
+	if ok, wait, err := store.Take("key", limit, now.Add(time.Minute)); err != nil || ok || wait != time.Minute {
+		t.Fatalf("expected to wait a minute, got %v: %v", wait, err)
+	}
+}
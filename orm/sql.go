@@ -15,6 +15,7 @@
 package orm
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"reflect"
@@ -377,7 +378,11 @@ func addRelated(relatedModelSchemas []*RelatedModelSchema, tableName string) ([]
 }
 
 func LoadOne(model QueryModel, queries map[string]interface{}) error {
-	if _, err := Load(model, queries, true); err != nil {
+	return LoadOneContext(context.Background(), model, queries)
+}
+
+func LoadOneContext(ctx context.Context, model QueryModel, queries map[string]interface{}) error {
+	if _, err := LoadContext(ctx, model, queries, true); err != nil {
 		return err
 	} else {
 		return nil
@@ -385,7 +390,11 @@ func LoadOne(model QueryModel, queries map[string]interface{}) error {
 }
 
 func LoadMany(model QueryModel, queries map[string]interface{}) ([]QueryModel, error) {
-	return Load(model, queries, false)
+	return LoadContext(context.Background(), model, queries, false)
+}
+
+func LoadManyContext(ctx context.Context, model QueryModel, queries map[string]interface{}) ([]QueryModel, error) {
+	return LoadContext(ctx, model, queries, false)
 }
 
 type QueryStmt struct {
@@ -395,10 +404,14 @@ type QueryStmt struct {
 }
 
 func (q *QueryStmt) Execute(args ...any) ([]QueryModel, error) {
+	return q.ExecuteContext(context.Background(), args...)
+}
+
+func (q *QueryStmt) ExecuteContext(ctx context.Context, args ...any) ([]QueryModel, error) {
 
 	models := make([]QueryModel, 0, 1000)
 
-	if rows, err := q.Model.Database()().Query(q.Query, args...); err != nil {
+	if rows, err := q.Model.Database()().QueryContext(ctx, q.Query, args...); err != nil {
 		return nil, err
 	} else {
 		defer rows.Close()
@@ -451,10 +464,14 @@ func (l *LoadStmt) AddValue(value interface{}) int {
 }
 
 func (l *LoadStmt) Execute() ([]QueryModel, error) {
+	return l.ExecuteContext(context.Background())
+}
+
+func (l *LoadStmt) ExecuteContext(ctx context.Context) ([]QueryModel, error) {
 
 	models := make([]QueryModel, 0)
 
-	if rows, err := l.Model.Database()().Query(l.Statement(), l.ConditionValues...); err != nil {
+	if rows, err := l.Model.Database()().QueryContext(ctx, l.Statement(), l.ConditionValues...); err != nil {
 		return nil, err
 	} else {
 		defer rows.Close()
@@ -609,8 +626,12 @@ func getConditions(tableName string, queries map[string]interface{}, offset int)
 }
 
 func DeleteMany(model QueryModel, queries map[string]interface{}) error {
+	return DeleteManyContext(context.Background(), model, queries)
+}
+
+func DeleteManyContext(ctx context.Context, model QueryModel, queries map[string]interface{}) error {
 	stmt := GetDeleteStmt(model, queries)
-	return stmt.Execute()
+	return stmt.ExecuteContext(ctx)
 }
 
 type UpdateStmt struct {
@@ -626,8 +647,12 @@ type Expression struct {
 }
 
 func Update(model QueryModel, queries map[string]interface{}, data map[string]interface{}) error {
+	return UpdateContext(context.Background(), model, queries, data)
+}
+
+func UpdateContext(ctx context.Context, model QueryModel, queries map[string]interface{}, data map[string]interface{}) error {
 	stmt := GetUpdateStmt(model, queries, data)
-	return stmt.Execute()
+	return stmt.ExecuteContext(ctx)
 }
 
 func GetUpdateStmt(model QueryModel, queries map[string]interface{}, data map[string]interface{}) *UpdateStmt {
@@ -668,7 +693,11 @@ func (u *UpdateStmt) Statement() string {
 }
 
 func (d *UpdateStmt) Execute() error {
-	if _, err := d.Model.Database()().Exec(d.Statement(), append(d.Values, d.ConditionValues...)...); err != nil {
+	return d.ExecuteContext(context.Background())
+}
+
+func (d *UpdateStmt) ExecuteContext(ctx context.Context) error {
+	if _, err := d.Model.Database()().ExecContext(ctx, d.Statement(), append(d.Values, d.ConditionValues...)...); err != nil {
 		return err
 	} else {
 		return nil
@@ -690,7 +719,11 @@ func (d *DeleteStmt) Statement() string {
 }
 
 func (d *DeleteStmt) Execute() error {
-	if _, err := d.Model.Database()().Exec(d.Statement(), d.ConditionValues...); err != nil {
+	return d.ExecuteContext(context.Background())
+}
+
+func (d *DeleteStmt) ExecuteContext(ctx context.Context) error {
+	if _, err := d.Model.Database()().ExecContext(ctx, d.Statement(), d.ConditionValues...); err != nil {
 		return err
 	} else {
 		return nil
@@ -758,6 +791,10 @@ func GetLoadStmt(model QueryModel, queries map[string]interface{}, single bool)
 }
 
 func Load(model QueryModel, queries map[string]interface{}, single bool) ([]QueryModel, error) {
+	return LoadContext(context.Background(), model, queries, single)
+}
+
+func LoadContext(ctx context.Context, model QueryModel, queries map[string]interface{}, single bool) ([]QueryModel, error) {
 
 	stmt, err := GetLoadStmt(model, queries, single)
 
@@ -765,11 +802,15 @@ func Load(model QueryModel, queries map[string]interface{}, single bool) ([]Quer
 		return nil, err
 	}
 
-	return LoadWithStmt(stmt)
+	return LoadWithStmtContext(ctx, stmt)
 }
 
 func LoadWithStmt(stmt *LoadStmt) ([]QueryModel, error) {
-	models, err := stmt.Execute()
+	return LoadWithStmtContext(context.Background(), stmt)
+}
+
+func LoadWithStmtContext(ctx context.Context, stmt *LoadStmt) ([]QueryModel, error) {
+	models, err := stmt.ExecuteContext(ctx)
 
 	if err != nil {
 		return nil, err
@@ -784,17 +825,25 @@ func LoadWithStmt(stmt *LoadStmt) ([]QueryModel, error) {
 }
 
 func Refresh(model QueryModel) error {
+	return RefreshContext(context.Background(), model)
+}
+
+func RefreshContext(ctx context.Context, model QueryModel) error {
 	if err := model.Init(); err != nil {
 		return err
 	}
 	if dbModel, ok := model.(*DBModel); !ok {
 		return fmt.Errorf("refreshing only supported for DB models")
 	} else {
-		return LoadOne(model, map[string]interface{}{"id": dbModel.ID})
+		return LoadOneContext(ctx, model, map[string]interface{}{"id": dbModel.ID})
 	}
 }
 
 func Delete(model QueryModel) error {
+	return DeleteContext(context.Background(), model)
+}
+
+func DeleteContext(ctx context.Context, model QueryModel) error {
 	if dbModel, ok := model.(*DBModel); !ok {
 		return fmt.Errorf("deleting only supported for DB models")
 	} else {
@@ -806,7 +855,7 @@ func Delete(model QueryModel) error {
 		WHERE id = $1
 		`, model.TableName())
 
-		_, err := model.Database()().Exec(deleteQuery, dbModel.ID)
+		_, err := model.Database()().ExecContext(ctx, deleteQuery, dbModel.ID)
 
 		if err != nil {
 			return err
@@ -821,6 +870,10 @@ func IsNull(value reflect.Value) bool {
 }
 
 func Save(model QueryModel) error {
+	return SaveContext(context.Background(), model)
+}
+
+func SaveContext(ctx context.Context, model QueryModel) error {
 
 	modelSchema := InferModelSchema(model)
 
@@ -929,7 +982,7 @@ func Save(model QueryModel) error {
 		returning,
 	)
 
-	if rows, err := model.Database()().Query(query, insertValues...); err != nil {
+	if rows, err := model.Database()().QueryContext(ctx, query, insertValues...); err != nil {
 		return err
 	} else {
 
@@ -0,0 +1,139 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+var errNoDatabase = errors.New("no database")
+
+// records the contexts of all queries, without running them
+type contextDB struct {
+	ctxs []context.Context
+}
+
+func (d *contextDB) record(ctx context.Context) error {
+	d.ctxs = append(d.ctxs, ctx)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return errNoDatabase
+}
+
+func (d *contextDB) Prepare(query string) (*sql.Stmt, error) {
+	return nil, errNoDatabase
+}
+
+func (d *contextDB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return d.ExecContext(context.Background(), query, args...)
+}
+
+func (d *contextDB) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return d.QueryContext(context.Background(), query, args...)
+}
+
+func (d *contextDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return nil, d.record(ctx)
+}
+
+func (d *contextDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, d.record(ctx)
+}
+
+func (d *contextDB) Settings() *DatabaseSettings {
+	return &DatabaseSettings{}
+}
+
+func (d *contextDB) Begin() (*sql.Tx, error) {
+	return nil, errNoDatabase
+}
+
+type contextModel struct {
+	DBModel
+	Name string `json:"name"`
+}
+
+func TestContextVariants(t *testing.T) {
+
+	db := &contextDB{}
+	dbFunc := func() DB { return db }
+
+	filters := map[string]any{"name": "test"}
+	mapper := &Mapper[*contextModel]{db: dbFunc}
+
+	model := func() QueryModel {
+		return Init(&DBModel{ID: 1}, dbFunc)
+	}
+
+	for name, query := range map[string]func(ctx context.Context) error{
+		"Mapper.ObjectsContext": func(ctx context.Context) error {
+			_, err := mapper.ObjectsContext(ctx, filters)
+			return err
+		},
+		"ObjectsContext": func(ctx context.Context) error {
+			_, err := ObjectsContext[contextModel](ctx, dbFunc, filters)
+			return err
+		},
+		"QueryContext": func(ctx context.Context) error {
+			_, err := QueryContext[contextModel](ctx, dbFunc, "SELECT * FROM context_model")
+			return err
+		},
+		"LoadOneContext": func(ctx context.Context) error {
+			return LoadOneContext(ctx, model(), filters)
+		},
+		"LoadManyContext": func(ctx context.Context) error {
+			_, err := LoadManyContext(ctx, model(), filters)
+			return err
+		},
+		"RefreshContext": func(ctx context.Context) error {
+			return RefreshContext(ctx, model())
+		},
+		"UpdateContext": func(ctx context.Context) error {
+			return UpdateContext(ctx, model(), filters, map[string]any{"name": "new"})
+		},
+		"DeleteManyContext": func(ctx context.Context) error {
+			return DeleteManyContext(ctx, model(), filters)
+		},
+		"DeleteContext": func(ctx context.Context) error {
+			return DeleteContext(ctx, model())
+		},
+	} {
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		db.ctxs = nil
+
+		if err := query(ctx); !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: expected the query to be canceled, got %v", name, err)
+			continue
+		}
+
+		if len(db.ctxs) != 1 || db.ctxs[0] != ctx {
+			t.Errorf("%s: expected the context to be passed to the database", name)
+		}
+	}
+
+	// the variants without a context use the background context
+	db.ctxs = nil
+
+	if _, err := mapper.Objects(filters); !errors.Is(err, errNoDatabase) || len(db.ctxs) != 1 || db.ctxs[0] != context.Background() {
+		t.Fatalf("expected the background context, got %v", err)
+	}
+}
@@ -0,0 +1,301 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package gospel
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// In contrast to templates, HTML documents (e.g. from users or other tools)
+// don't contain macros or expressions, but use HTML features like boolean
+// attributes, comments, unclosed tags and character references. We parse
+// them leniently, like browsers do.
+
+const maxHTMLDepth = 512
+
+var voidTags = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "command": true, "embed": true,
+	"hr": true, "img": true, "input": true, "keygen": true, "link": true, "meta": true,
+	"param": true, "source": true, "track": true, "wbr": true,
+}
+
+// the content of these tags isn't parsed as HTML
+var rawTextTags = map[string]bool{
+	"script": true, "style": true, "textarea": true, "title": true, "xmp": true,
+}
+
+// tags that implicitly close an open <p> tag
+var paragraphClosers = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "details": true,
+	"div": true, "dl": true, "fieldset": true, "figcaption": true, "figure": true,
+	"footer": true, "form": true, "h1": true, "h2": true, "h3": true, "h4": true,
+	"h5": true, "h6": true, "header": true, "hr": true, "main": true, "nav": true,
+	"ol": true, "p": true, "pre": true, "section": true, "table": true, "ul": true,
+}
+
+// Checks whether the start tag implicitly closes the open element
+func closesImplicitly(open, tag string) bool {
+	switch open {
+	case "p":
+		return paragraphClosers[tag]
+	case "li":
+		return tag == "li"
+	case "dt", "dd":
+		return tag == "dt" || tag == "dd"
+	case "option":
+		return tag == "option" || tag == "optgroup"
+	case "tr":
+		return tag == "tr" || tag == "tbody" || tag == "tfoot"
+	case "td", "th":
+		return tag == "td" || tag == "th" || tag == "tr" || tag == "tbody" || tag == "tfoot"
+	case "thead", "tbody":
+		return tag == "tbody" || tag == "tfoot"
+	}
+	return false
+}
+
+var htmlTagNameRegexp = regexp.MustCompile(`^[a-zA-Z][^\s/>]*`)
+var htmlAttributeNameRegexp = regexp.MustCompile(`^[^\s"'>/=]+`)
+var htmlUnquotedValueRegexp = regexp.MustCompile(`^[^\s>]+`)
+
+// Parses an HTML document or fragment into a list of elements
+func (p *Parser) ParseHTML(source string) ([]any, error) {
+
+	p.Pos = 0
+	p.Source = source
+
+	children, _, err := p.parseHTMLNodes(nil)
+
+	return children, err
+}
+
+// Parses nodes until the closing tag of an open element. Returns the tag
+// of the element that was closed, which is usually the innermost one.
+func (p *Parser) parseHTMLNodes(open []string) ([]any, string, error) {
+
+	if len(open) > maxHTMLDepth {
+		return nil, "", fmt.Errorf("elements are nested too deeply")
+	}
+
+	nodes := make([]any, 0)
+	current := ""
+
+	if len(open) > 0 {
+		current = open[len(open)-1]
+	}
+
+	for p.Pos < len(p.Source) {
+
+		rest := p.Source[p.Pos:]
+
+		switch {
+		case strings.HasPrefix(rest, "<!--"):
+			// we drop comments
+			if end := strings.Index(rest[4:], "-->"); end == -1 {
+				p.Pos = len(p.Source)
+			} else {
+				p.Pos += 4 + end + 3
+			}
+		case strings.HasPrefix(rest, "<!") || strings.HasPrefix(rest, "<?"):
+			// doctypes and processing instructions
+			if end := strings.Index(rest, ">"); end == -1 {
+				p.Pos = len(p.Source)
+			} else {
+				p.Pos += end + 1
+			}
+		case strings.HasPrefix(rest, "</") && htmlTagNameRegexp.MatchString(rest[2:]):
+
+			tag := strings.ToLower(htmlTagNameRegexp.FindString(rest[2:]))
+
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] == tag {
+					if i == len(open)-1 {
+						p.skipTag()
+					}
+					// closing tags of outer elements close this one as well
+					return nodes, tag, nil
+				}
+			}
+
+			// we ignore closing tags without an open element
+			p.skipTag()
+
+		case strings.HasPrefix(rest, "<") && htmlTagNameRegexp.MatchString(rest[1:]):
+
+			tag := strings.ToLower(htmlTagNameRegexp.FindString(rest[1:]))
+
+			if current != "" && closesImplicitly(current, tag) {
+				return nodes, current, nil
+			}
+
+			element, err := p.parseHTMLTag(open)
+
+			if err != nil {
+				return nil, "", err
+			}
+
+			nodes = append(nodes, element)
+
+		default:
+
+			// text continues until the next tag (or '<' that can start one)
+			end := 1 + strings.Index(rest[1:], "<")
+
+			if end == 0 {
+				end = len(rest)
+			}
+
+			nodes = append(nodes, Literal(html.UnescapeString(rest[:end])))
+			p.Pos += end
+		}
+	}
+
+	return nodes, "", nil
+}
+
+func (p *Parser) skipTag() {
+	if end := strings.Index(p.Source[p.Pos:], ">"); end == -1 {
+		p.Pos = len(p.Source)
+	} else {
+		p.Pos += end + 1
+	}
+}
+
+func (p *Parser) parseHTMLTag(open []string) (*HTMLElement, error) {
+
+	// we consume the '<'
+	p.Pos++
+
+	tag := strings.ToLower(htmlTagNameRegexp.FindString(p.Source[p.Pos:]))
+	p.Pos += len(tag)
+
+	e := &HTMLElement{
+		Tag:        tag,
+		Void:       voidTags[tag],
+		Attributes: p.parseHTMLAttributes(),
+	}
+
+	selfClosing := strings.HasPrefix(p.Source[p.Pos:], "/>")
+	p.skipTag()
+
+	if e.Void || selfClosing {
+		return e, nil
+	}
+
+	if rawTextTags[tag] {
+
+		rest := p.Source[p.Pos:]
+		end := strings.Index(strings.ToLower(rest), "</"+tag)
+
+		if end == -1 {
+			end = len(rest)
+		}
+
+		text := rest[:end]
+
+		if tag == "textarea" || tag == "title" {
+			// these can contain character references
+			text = html.UnescapeString(text)
+		}
+
+		if text != "" {
+			e.Children = []any{Literal(text)}
+		}
+
+		p.Pos += end
+
+		if p.Pos < len(p.Source) {
+			p.skipTag()
+		}
+
+		return e, nil
+	}
+
+	children, _, err := p.parseHTMLNodes(append(open, tag))
+
+	if err != nil {
+		return nil, err
+	}
+
+	e.Children = children
+
+	return e, nil
+}
+
+func (p *Parser) parseHTMLAttributes() []*HTMLAttribute {
+
+	attributes := make([]*HTMLAttribute, 0)
+
+	for p.Pos < len(p.Source) {
+
+		p.Pos += len(p.Source[p.Pos:]) - len(strings.TrimLeft(p.Source[p.Pos:], " \t\n\r\f"))
+
+		rest := p.Source[p.Pos:]
+
+		if rest == "" || rest[0] == '>' || strings.HasPrefix(rest, "/>") {
+			break
+		}
+
+		if rest[0] == '/' {
+			p.Pos++
+			continue
+		}
+
+		name := htmlAttributeNameRegexp.FindString(rest)
+
+		if name == "" {
+			// we skip invalid characters
+			p.Pos++
+			continue
+		}
+
+		p.Pos += len(name)
+		a := &HTMLAttribute{Name: strings.ToLower(name)}
+
+		rest = strings.TrimLeft(p.Source[p.Pos:], " \t\n\r\f")
+
+		if strings.HasPrefix(rest, "=") {
+
+			rest = strings.TrimLeft(rest[1:], " \t\n\r\f")
+			p.Pos = len(p.Source) - len(rest)
+
+			var value string
+
+			if rest != "" && (rest[0] == '"' || rest[0] == '\'') {
+				if end := strings.IndexByte(rest[1:], rest[0]); end == -1 {
+					value = rest[1:]
+					p.Pos = len(p.Source)
+				} else {
+					value = rest[1 : end+1]
+					p.Pos += end + 2
+				}
+			} else {
+				value = htmlUnquotedValueRegexp.FindString(rest)
+				p.Pos += len(value)
+			}
+
+			a.Value = html.UnescapeString(value)
+		}
+
+		// boolean attributes have a nil value
+
+		attributes = append(attributes, a)
+	}
+
+	return attributes
+}
@@ -0,0 +1,274 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package gospel
+
+import (
+	"container/list"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// A token bucket that holds up to Burst tokens and is refilled with Rate
+// tokens per second. Every request takes one token.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+func PerSecond(n int, burst int) RateLimit {
+	return RateLimit{Rate: float64(n), Burst: burst}
+}
+
+func PerMinute(n int, burst int) RateLimit {
+	return RateLimit{Rate: float64(n) / 60, Burst: burst}
+}
+
+func PerHour(n int, burst int) RateLimit {
+	return RateLimit{Rate: float64(n) / 3600, Burst: burst}
+}
+
+// Refills a bucket with the given number of tokens that was last updated
+// at the given time, and tries to take a token from it. Returns the new
+// number of tokens, whether we got a token and otherwise how long to wait.
+func (r RateLimit) Take(tokens float64, updated, now time.Time) (float64, bool, time.Duration) {
+
+	if elapsed := now.Sub(updated).Seconds(); elapsed > 0 {
+		tokens = math.Min(float64(r.Burst), tokens+elapsed*r.Rate)
+	}
+
+	if tokens >= 1 {
+		return tokens - 1, true, 0
+	}
+
+	if r.Rate <= 0 {
+		return tokens, false, time.Duration(math.MaxInt64)
+	}
+
+	wait := time.Duration((1 - tokens) / r.Rate * float64(time.Second))
+
+	return tokens, false, wait
+}
+
+type RateLimitStore interface {
+	// Takes a token from the bucket with the given key
+	Take(key string, limit RateLimit, now time.Time) (bool, time.Duration, error)
+}
+
+type bucket struct {
+	key     string
+	tokens  float64
+	updated time.Time
+}
+
+// we remove full buckets when the store grows beyond this size
+const maxInMemoryBuckets = 100000
+
+type InMemoryRateLimitStore struct {
+	mutex   sync.Mutex
+	buckets map[string]*list.Element
+	// the buckets ordered by their last update, the oldest one last
+	order *list.List
+}
+
+func MakeInMemoryRateLimitStore() *InMemoryRateLimitStore {
+	return &InMemoryRateLimitStore{
+		buckets: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (i *InMemoryRateLimitStore) Take(key string, limit RateLimit, now time.Time) (bool, time.Duration, error) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	element, ok := i.buckets[key]
+
+	if ok {
+		i.order.MoveToFront(element)
+	} else {
+
+		if len(i.buckets) >= maxInMemoryBuckets {
+			i.prune(limit, now)
+		}
+
+		element = i.order.PushFront(&bucket{key: key, tokens: float64(limit.Burst), updated: now})
+		i.buckets[key] = element
+	}
+
+	b := element.Value.(*bucket)
+
+	tokens, allowed, wait := limit.Take(b.tokens, b.updated, now)
+	b.tokens, b.updated = tokens, now
+
+	return allowed, wait, nil
+}
+
+// Removes buckets that would be full again, as they're the same as new ones.
+// We start with the oldest ones and stop at the first one that isn't full, so
+// we don't have to look at all buckets.
+func (i *InMemoryRateLimitStore) prune(limit RateLimit, now time.Time) {
+	for i.order.Len() > 0 {
+
+		oldest := i.order.Back()
+		b := oldest.Value.(*bucket)
+
+		if tokens, _, _ := limit.Take(b.tokens, b.updated, now); tokens < float64(limit.Burst)-1 {
+			return
+		}
+
+		i.order.Remove(oldest)
+		delete(i.buckets, b.key)
+	}
+}
+
+// Returns the number of buckets in the store
+func (i *InMemoryRateLimitStore) Len() int {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	return i.order.Len()
+}
+
+// Returns the key of the bucket for a request
+type KeyFunc func(c Context) string
+
+// Uses the IP address of the client. If the app runs behind a proxy,
+// use a custom key function that reads the address from a trusted header.
+func ByIP(c Context) string {
+
+	addr := c.Request().RemoteAddr
+
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+
+	return addr
+}
+
+// Uses a random ID stored in the session, together with the IP address,
+// so that clients behind the same address (e.g. a NAT) get their own
+// buckets. The session is stored in a cookie that clients control, so
+// this is not a security control: a client can send a new ID with each
+// request. To limit abuse, use ByIP or a key from a server-validated
+// identity (e.g. ByUser of the auth manager).
+func BySession(c Context) string {
+
+	ip := "ip:" + ByIP(c)
+	sessionId := PersistentGlobalVar(c, "gospel.ratelimit.session", "")
+
+	if id := sessionId.Get(); id != "" {
+		return ip + ":session:" + id
+	}
+
+	id, err := RandomId()
+
+	if err != nil {
+		Log.Error("Cannot generate session ID: %v", err)
+	} else {
+		sessionId.Set(id)
+	}
+
+	return ip
+}
+
+type RateLimiter struct {
+	// used to separate the buckets of different limiters in the store
+	Name  string
+	Limit RateLimit
+	Key   KeyFunc
+	Store RateLimitStore
+}
+
+// Makes a rate limiter with an in-memory store. The key function defaults to ByIP.
+func MakeRateLimiter(name string, limit RateLimit, key KeyFunc) *RateLimiter {
+
+	if key == nil {
+		key = ByIP
+	}
+
+	return &RateLimiter{
+		Name:  name,
+		Limit: limit,
+		Key:   key,
+		Store: MakeInMemoryRateLimitStore(),
+	}
+}
+
+type rateLimitResult struct {
+	allowed bool
+	wait    time.Duration
+}
+
+// Takes a token for the current request. As element functions can be
+// executed repeatedly, we only take one token per limiter and request.
+func (r *RateLimiter) Allow(c Context) (bool, time.Duration) {
+
+	resultKey := fmt.Sprintf("gospel.ratelimit.%s", r.Name)
+
+	if variable := c.GetVar(resultKey); variable != nil {
+		if result, ok := variable.GetRaw().(*rateLimitResult); ok {
+			return result.allowed, result.wait
+		}
+	}
+
+	allowed, wait, err := r.Store.Take(r.Name+":"+r.Key(c), r.Limit, time.Now())
+
+	if err != nil {
+		// we don't lock out users if the store doesn't work
+		Log.Error("Cannot check rate limit '%s': %v", r.Name, err)
+		allowed, wait = true, 0
+	}
+
+	GlobalVar(c, resultKey, &rateLimitResult{allowed, wait})
+
+	return allowed, wait
+}
+
+// Sets the status code to 429 and the Retry-After header
+func TooManyRequests(c Context, wait time.Duration) Element {
+
+	seconds := int64(math.Ceil(wait.Seconds()))
+
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	c.ResponseWriter().Header().Set("Retry-After", strconv.FormatInt(seconds, 10))
+	c.SetStatusCode(http.StatusTooManyRequests)
+
+	return F("Too many requests")
+}
+
+// Adds a rate limiter to the route
+func (r *RouteConfig) Limit(limiter *RateLimiter) *RouteConfig {
+	r.limiters = append(r.limiters, limiter)
+	return r
+}
+
+// Wraps a submit handler so that it's only called if the rate limit isn't
+// exceeded, e.g. Func[any](c, RateLimited(c, limiter, func() { ... }))
+func RateLimited(c Context, limiter *RateLimiter, handler func()) func() {
+	return func() {
+		if allowed, wait := limiter.Allow(c); !allowed {
+			TooManyRequests(c, wait)
+			return
+		}
+		handler()
+	}
+}
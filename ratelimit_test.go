@@ -0,0 +1,128 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package gospel
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+
+	store := MakeInMemoryRateLimitStore()
+	limit := PerSecond(1, 2)
+	now := time.Now()
+
+	for i, expected := range []bool{true, true, false} {
+		if allowed, _, _ := store.Take("key", limit, now); allowed != expected {
+			t.Fatalf("request %d: expected %v", i, expected)
+		}
+	}
+
+	if _, _, wait := limit.Take(0.5, now, now); wait != 500*time.Millisecond {
+		t.Fatalf("unexpected wait time: %s", wait)
+	}
+
+	// the bucket is refilled over time
+	if allowed, _, _ := store.Take("key", limit, now.Add(time.Second)); !allowed {
+		t.Fatalf("expected a refilled bucket")
+	}
+
+	if allowed, _, _ := store.Take("other", limit, now); !allowed {
+		t.Fatalf("expected separate buckets per key")
+	}
+}
+
+func TestRateLimitPruning(t *testing.T) {
+
+	store := MakeInMemoryRateLimitStore()
+	limit := PerSecond(1, 2)
+	now := time.Now()
+
+	for i := 0; i < maxInMemoryBuckets; i++ {
+		store.Take(fmt.Sprintf("key-%d", i), limit, now)
+	}
+
+	later := now.Add(2 * time.Second)
+
+	// the first bucket is empty now, and the most recently used one
+	store.Take("key-0", limit, later)
+	store.Take("key-0", limit, later)
+
+	// all other buckets are full again, so they're removed
+	store.Take("new", limit, later)
+
+	if store.Len() != 2 {
+		t.Fatalf("expected 2 buckets, got %d", store.Len())
+	}
+
+	if allowed, _, _ := store.Take("key-0", limit, later); allowed {
+		t.Fatalf("expected the empty bucket to be kept")
+	}
+}
+
+func TestRateLimitedRoute(t *testing.T) {
+
+	limiter := MakeRateLimiter("test", PerMinute(1, 1), nil)
+
+	render := func() (*DefaultContext, *httptest.ResponseRecorder) {
+		w := httptest.NewRecorder()
+		c := MakeDefaultContext(httptest.NewRequest("GET", "/form", nil), w, MakeStore(MakeCookieStore("")))
+		MakeRouter(c)
+		c.Execute(func(c Context) Element {
+			return UseRouter(c).Match(c, Route("/form", Div("form")).Limit(limiter))
+		})
+		return c, w
+	}
+
+	if c, _ := render(); c.StatusCode() != 200 {
+		t.Fatalf("expected the first request to be allowed")
+	}
+
+	c, w := render()
+
+	if c.StatusCode() != 429 || w.Header().Get("Retry-After") != "60" {
+		t.Fatalf("expected a 429 with Retry-After, got %d and '%s'", c.StatusCode(), w.Header().Get("Retry-After"))
+	}
+}
+
+func TestBySession(t *testing.T) {
+
+	key := func(addr, sessionId string) string {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.RemoteAddr = addr
+		c := MakeDefaultContext(r, httptest.NewRecorder(), MakeStore(MakeCookieStore("")))
+		if sessionId != "" {
+			// the client can put any ID into the session cookie
+			PersistentGlobalVar(c, "gospel.ratelimit.session", sessionId)
+		}
+		return BySession(c)
+	}
+
+	if k := key("1.2.3.4:1000", ""); k != "ip:1.2.3.4" {
+		t.Fatalf("expected the IP address for new sessions, got '%s'", k)
+	}
+
+	if k := key("1.2.3.4:1000", "victim"); k != "ip:1.2.3.4:session:victim" {
+		t.Fatalf("unexpected key '%s'", k)
+	}
+
+	// a forged ID can't exhaust the bucket of a client with another address
+	if key("5.6.7.8:1000", "victim") == key("1.2.3.4:1000", "victim") {
+		t.Fatalf("expected separate buckets for different addresses")
+	}
+}
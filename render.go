@@ -0,0 +1,292 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package gospel
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+type RenderMode int
+
+const (
+	// renders elements like RenderElement does
+	DefaultRender RenderMode = iota
+	// indents elements and puts each of them on its own line
+	PrettyRender
+	// collapses whitespace in text
+	MinifiedRender
+)
+
+type RenderOptions struct {
+	Mode RenderMode
+	// the indentation of pretty output, two spaces by default
+	Indent string
+	// omits the quotes of attribute values where possible (minified only)
+	OmitQuotes bool
+}
+
+var PrettyRenderOptions = &RenderOptions{Mode: PrettyRender}
+var MinifiedRenderOptions = &RenderOptions{Mode: MinifiedRender, OmitQuotes: true}
+
+// whitespace is significant in these tags, so we don't change their content
+var preformattedTags = map[string]bool{
+	"pre": true, "textarea": true, "script": true, "style": true,
+}
+
+var whitespaceRegexp = regexp.MustCompile(`\s+`)
+
+// characters that require an attribute value to be quoted
+var unquotedValueRegexp = regexp.MustCompile("^[^\\s\"'=<>`]+$")
+
+// Renders the element with the given options (nil renders it like
+// RenderElement does)
+func RenderWith(element Element, options *RenderOptions) string {
+
+	if element == nil {
+		return ""
+	}
+
+	htmlElement, ok := element.(*HTMLElement)
+
+	if !ok || options == nil || options.Mode == DefaultRender {
+		return element.RenderElement()
+	}
+
+	r := &renderer{options: options, indent: options.Indent}
+
+	if r.indent == "" {
+		r.indent = "  "
+	}
+
+	if options.Mode == PrettyRender {
+		r.pretty(htmlElement, 0)
+		return strings.TrimSuffix(r.b.String(), "\n")
+	}
+
+	r.minified(htmlElement, false)
+
+	return r.b.String()
+}
+
+type renderer struct {
+	options *RenderOptions
+	indent  string
+	b       strings.Builder
+}
+
+// Returns the renderable children of the element
+func renderableChildren(h *HTMLElement) []*HTMLElement {
+
+	children := make([]*HTMLElement, 0, len(h.Children))
+
+	for _, child := range h.Children {
+
+		htmlChild, ok := child.(*HTMLElement)
+
+		if !ok {
+			if htmlFuncChild, ok := child.(PureElementFunction); ok {
+				htmlChild, _ = htmlFuncChild().(*HTMLElement)
+			}
+		}
+
+		if htmlChild != nil {
+			children = append(children, htmlChild)
+		}
+	}
+
+	return children
+}
+
+// Writes the opening tag without its closing '>'. A value followed by a
+// '/' has to be quoted, as the slash would otherwise be part of it.
+func (r *renderer) openTag(h *HTMLElement, selfClosing bool) {
+
+	r.b.WriteString("<" + h.Tag)
+
+	if r.options.Mode != MinifiedRender || !r.options.OmitQuotes {
+		for _, attribute := range h.Attributes {
+			if ra := attribute.RenderAttribute(); ra != "" {
+				r.b.WriteString(" " + ra)
+			}
+		}
+		return
+	}
+
+	type attribute struct {
+		name, value string
+		boolean     bool
+	}
+
+	rendered := make([]attribute, 0, len(h.Attributes))
+
+	for _, a := range h.Attributes {
+
+		if a.Hidden {
+			continue
+		}
+
+		if a.Value == nil {
+			rendered = append(rendered, attribute{name: html.EscapeString(a.Name), boolean: true})
+			continue
+		}
+
+		if value, ok := a.escapedValue(); ok {
+			rendered = append(rendered, attribute{name: html.EscapeString(a.Name), value: value})
+		}
+	}
+
+	for i, a := range rendered {
+
+		r.b.WriteString(" " + a.name)
+
+		if a.boolean {
+			continue
+		}
+
+		if unquotedValueRegexp.MatchString(a.value) && !(selfClosing && i == len(rendered)-1) {
+			r.b.WriteString("=" + a.value)
+		} else {
+			r.b.WriteString("=\"" + a.value + "\"")
+		}
+	}
+}
+
+// Writes the tags of the element around its children
+func (r *renderer) element(h *HTMLElement, empty bool, children func()) {
+
+	if h.Void {
+		r.openTag(h, false)
+		// void elements don't need a closing slash in HTML
+		if r.options.Mode == MinifiedRender {
+			r.b.WriteString(">")
+		} else {
+			r.b.WriteString("/>")
+		}
+		return
+	}
+
+	// in contrast to HTML elements, empty foreign elements can be self-closing
+	if h.Namespace != "" && empty {
+		r.openTag(h, true)
+		r.b.WriteString("/>")
+		return
+	}
+
+	r.openTag(h, false)
+	r.b.WriteString(">")
+	children()
+	r.b.WriteString("</" + h.Tag + ">")
+}
+
+func (r *renderer) minified(h *HTMLElement, preformatted bool) {
+
+	if strValue, ok := h.Value.(string); ok {
+
+		if h.Safe {
+			r.b.WriteString(strValue)
+			return
+		}
+
+		if !preformatted {
+			strValue = whitespaceRegexp.ReplaceAllString(strValue, " ")
+		}
+
+		r.b.WriteString(html.EscapeString(strValue))
+		return
+	}
+
+	preformatted = preformatted || preformattedTags[h.Tag]
+	children := renderableChildren(h)
+
+	writeChildren := func() {
+		for _, child := range children {
+			r.minified(child, preformatted)
+		}
+	}
+
+	if h.Tag == "" {
+		writeChildren()
+		return
+	}
+
+	r.element(h, len(children) == 0, writeChildren)
+}
+
+func (r *renderer) pretty(h *HTMLElement, depth int) {
+
+	prefix := strings.Repeat(r.indent, depth)
+
+	if strValue, ok := h.Value.(string); ok {
+
+		text := strings.TrimSpace(strValue)
+
+		if text == "" {
+			return
+		}
+
+		if !h.Safe {
+			text = html.EscapeString(text)
+		}
+
+		r.b.WriteString(prefix + text + "\n")
+		return
+	}
+
+	children := renderableChildren(h)
+
+	if h.Tag == "" {
+		for _, child := range children {
+			r.pretty(child, depth)
+		}
+		return
+	}
+
+	r.b.WriteString(prefix)
+
+	if preformattedTags[h.Tag] {
+		// we keep the content as it is (the children are already resolved,
+		// so we don't execute element functions again)
+		r.element(h, len(children) == 0, func() {
+			for _, child := range children {
+				r.b.WriteString(child.RenderElement())
+			}
+		})
+		r.b.WriteString("\n")
+		return
+	}
+
+	// elements that only contain text stay on one line
+	if len(children) == 0 || len(children) == 1 && children[0].Value != nil {
+		r.element(h, len(children) == 0, func() {
+			for _, child := range children {
+				r.b.WriteString(strings.TrimSpace(child.RenderElement()))
+			}
+		})
+		r.b.WriteString("\n")
+		return
+	}
+
+	r.element(h, false, func() {
+		r.b.WriteString("\n")
+		for _, child := range children {
+			r.pretty(child, depth+1)
+		}
+		r.b.WriteString(prefix)
+	})
+
+	r.b.WriteString("\n")
+}
@@ -0,0 +1,104 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package gospel
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"strings"
+	"testing"
+)
+
+func renderTestElement() Element {
+	return F(
+		Doctype("html"),
+		Div(
+			Class("box main"),
+			Id("x"),
+			H1("  Hello   world  "),
+			P("Some ", B("bold"), " text"),
+			Pre("line 1\n  line 2"),
+			Input(Type("checkbox"), CheckedAttr()),
+			Span(),
+		),
+	)
+}
+
+func TestRenderModes(t *testing.T) {
+
+	pretty := `<!doctype html>
+<div class="box main" id="x">
+  <h1>Hello   world</h1>
+  <p>
+    Some
+    <b>bold</b>
+    text
+  </p>
+  <pre>line 1
+  line 2</pre>
+  <input type="checkbox" checked/>
+  <span></span>
+</div>`
+
+	if diff := cmp.Diff(pretty, RenderWith(renderTestElement(), PrettyRenderOptions)); diff != "" {
+		t.Fatalf("unexpected pretty output: %s", diff)
+	}
+
+	minified := `<!doctype html><div class="box main" id=x><h1> Hello world </h1><p>Some <b>bold</b> text</p>` +
+		"<pre>line 1\n  line 2</pre>" + `<input type=checkbox checked><span></span></div>`
+
+	if diff := cmp.Diff(minified, RenderWith(renderTestElement(), MinifiedRenderOptions)); diff != "" {
+		t.Fatalf("unexpected minified output: %s", diff)
+	}
+
+	// values of void and self-closing elements can't swallow the slash
+	for element, expected := range map[*HTMLElement]string{
+		Img(Src("/a.png"), Alt("logo")):                       `<img src=/a.png alt=logo>`,
+		Input(Value(""), Type("text")):                        `<input value="" type=text>`,
+		ForeignTag(SVGNamespace, "path")(Attrib("d")("M0,0")): `<path d="M0,0"/>`,
+		ForeignTag(SVGNamespace, "use")(Href("#a")):           `<use href="#a"/>`,
+	} {
+		if html := RenderWith(element, MinifiedRenderOptions); html != expected {
+			t.Fatalf("expected %s, got %s", expected, html)
+		}
+	}
+
+	// without options, we render elements as usual
+	if RenderWith(renderTestElement(), nil) != renderTestElement().RenderElement() {
+		t.Fatalf("expected the default output")
+	}
+}
+
+func TestRenderElementFunctionsOnce(t *testing.T) {
+
+	for _, options := range []*RenderOptions{PrettyRenderOptions, MinifiedRenderOptions} {
+
+		executions := 0
+
+		element := Div(Pre(func() Element {
+			executions++
+			return Span("line 1\n  line 2")
+		}))
+
+		expected := "<span>line 1\n  line 2</span>"
+
+		if html := RenderWith(element, options); !strings.Contains(html, expected) {
+			t.Fatalf("expected %q in %q", expected, html)
+		}
+
+		if executions != 1 {
+			t.Fatalf("expected one execution, got %d", executions)
+		}
+	}
+}
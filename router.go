@@ -51,6 +51,39 @@ type RouteConfig struct {
 	ElementFunc any            `json:"element" graph:"include"`
 	regexp      *regexp.Regexp `json:"-"`
 	err         error          `json:"-"`
+	guards      []RouteGuard   `json:"-"`
+	limiters    []*RateLimiter `json:"-"`
+}
+
+// Checks whether the current request may access a route
+type RouteGuard func(c Context) bool
+
+// Adds guards to the route. If one of them fails, we respond with a 403
+// status and render the Forbidden element of the app instead.
+func (r *RouteConfig) Guard(guards ...RouteGuard) *RouteConfig {
+	r.guards = append(r.guards, guards...)
+	return r
+}
+
+func (r *RouteConfig) allowed(c Context) bool {
+	for _, guard := range r.guards {
+		if !guard(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// Sets the status code to 403 and returns the Forbidden element of the app
+func Forbidden(c Context) Element {
+
+	c.SetStatusCode(http.StatusForbidden)
+
+	if app := c.App(); app != nil && app.Forbidden != nil {
+		return app.Forbidden(c)
+	}
+
+	return F("Forbidden")
 }
 
 func (r *RouteConfig) Match(context Context, router *Router, generate bool) (Element, error) {
@@ -85,6 +118,16 @@ func (r *RouteConfig) Match(context Context, router *Router, generate bool) (Ele
 			Fragments: match[1:],
 		}
 
+		if !r.allowed(context) {
+			return Forbidden(context), nil
+		}
+
+		for _, limiter := range r.limiters {
+			if allowed, wait := limiter.Allow(context); !allowed {
+				return TooManyRequests(context, wait), nil
+			}
+		}
+
 		name := fmt.Sprintf("route.%s", r.Route)
 
 		if generate {
@@ -195,6 +238,21 @@ func (r *Router) RedirectedTo() string {
 	return r.redirectedTo
 }
 
+// Returns the path that the response redirects to. We don't redirect GET
+// requests to their own path (which would loop), but render the page.
+func (r *Router) Redirect() (string, bool) {
+
+	if r.redirectedTo == "" {
+		return "", false
+	}
+
+	if request := r.context.Request(); request != nil && request.Method == http.MethodGet && r.redirectedTo == request.URL.Path {
+		return "", false
+	}
+
+	return r.redirectedTo, true
+}
+
 func Route(route string, elementFunc ...any) *RouteConfig {
 
 	var element any
@@ -0,0 +1,231 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package gospel
+
+import (
+	"strings"
+)
+
+// Describes which tags and attributes are allowed in untrusted HTML
+type SanitizePolicy struct {
+	// allowed tags with their allowed attributes
+	Tags map[string][]string
+	// attributes that are allowed on all allowed tags
+	GlobalAttributes []string
+	// allowed schemes for URL attributes, relative URLs are always allowed
+	URLSchemes []string
+	// if set, we add a rel attribute with this value to all links
+	LinkRel string
+}
+
+// the content of these tags is removed along with the tags, for other
+// tags that aren't allowed we keep the content
+var droppedContentTags = map[string]bool{
+	"script": true, "style": true, "template": true, "iframe": true, "object": true,
+	"embed": true, "noscript": true, "textarea": true, "select": true, "svg": true,
+	"math": true, "head": true, "title": true, "xmp": true,
+}
+
+var basicFormattingTags = map[string][]string{
+	"b": nil, "strong": nil, "i": nil, "em": nil, "u": nil, "s": nil, "del": nil,
+	"ins": nil, "mark": nil, "small": nil, "sub": nil, "sup": nil, "br": nil,
+	"p": nil, "code": nil, "pre": nil, "blockquote": nil, "ul": nil, "ol": nil,
+	"li": nil, "hr": nil, "span": nil,
+}
+
+// Only allows text, all tags are removed
+var StrictTextPolicy = &SanitizePolicy{}
+
+// Allows basic formatting like bold text, lists and paragraphs
+var BasicFormattingPolicy = &SanitizePolicy{
+	Tags: basicFormattingTags,
+}
+
+// Allows user-generated content with formatting, headings, tables, links
+// and images. Links get rel="nofollow noopener noreferrer".
+var UGCPolicy = &SanitizePolicy{
+	Tags: mergeTags(basicFormattingTags, map[string][]string{
+		"a":          {"href", "title"},
+		"img":        {"src", "alt", "title", "width", "height"},
+		"h1":         nil,
+		"h2":         nil,
+		"h3":         nil,
+		"h4":         nil,
+		"h5":         nil,
+		"h6":         nil,
+		"table":      nil,
+		"thead":      nil,
+		"tbody":      nil,
+		"tfoot":      nil,
+		"tr":         nil,
+		"th":         {"colspan", "rowspan", "scope"},
+		"td":         {"colspan", "rowspan"},
+		"caption":    nil,
+		"dl":         nil,
+		"dt":         nil,
+		"dd":         nil,
+		"figure":     nil,
+		"figcaption": nil,
+		"abbr":       {"title"},
+		"cite":       nil,
+		"q":          {"cite"},
+		"div":        nil,
+	}),
+	GlobalAttributes: []string{"lang", "dir"},
+	URLSchemes:       []string{"http", "https", "mailto"},
+	LinkRel:          "nofollow noopener noreferrer",
+}
+
+func mergeTags(maps ...map[string][]string) map[string][]string {
+	merged := make(map[string][]string)
+	for _, m := range maps {
+		for key, value := range m {
+			merged[key] = value
+		}
+	}
+	return merged
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *SanitizePolicy) allowedURL(url string) bool {
+
+	i := strings.IndexAny(url, ":/?#")
+
+	if i == -1 || url[i] != ':' {
+		// this is a relative URL
+		return true
+	}
+
+	if SanitizeURL(url) == unsafeURL {
+		return false
+	}
+
+	scheme := strings.ToLower(strings.TrimSpace(url[:i]))
+
+	return containsString(s.URLSchemes, scheme)
+}
+
+func (s *SanitizePolicy) sanitizeAttributes(tag string, attributes []*HTMLAttribute) []*HTMLAttribute {
+
+	allowed, _ := s.Tags[tag]
+	sanitized := make([]*HTMLAttribute, 0, len(attributes))
+
+	for _, attribute := range attributes {
+
+		name := attribute.Name
+
+		if !containsString(allowed, name) && !containsString(s.GlobalAttributes, name) {
+			continue
+		}
+
+		// we never allow event handlers, even if the policy lists them
+		if isEventAttribute(name) {
+			continue
+		}
+
+		value, _ := attribute.Value.(string)
+
+		if isURLAttribute(name) && !s.allowedURL(strings.TrimSpace(value)) {
+			continue
+		}
+
+		if name == "style" {
+			if value = SanitizeCSS(value); value == unsafeCSS {
+				continue
+			}
+		}
+
+		sanitized = append(sanitized, &HTMLAttribute{
+			Name:  name,
+			Value: attribute.Value,
+		})
+	}
+
+	if tag == "a" && s.LinkRel != "" {
+		sanitized = append(sanitized, &HTMLAttribute{Name: "rel", Value: s.LinkRel})
+	}
+
+	return sanitized
+}
+
+func (s *SanitizePolicy) sanitizeNodes(nodes []any) []any {
+
+	sanitized := make([]any, 0, len(nodes))
+
+	for _, node := range nodes {
+
+		element, ok := node.(*HTMLElement)
+
+		if !ok || element == nil {
+			continue
+		}
+
+		if element.Tag == "" {
+			// this is a text node, which we always escape
+			if text, ok := element.Value.(string); ok {
+				sanitized = append(sanitized, Literal(text))
+			}
+			continue
+		}
+
+		if droppedContentTags[element.Tag] {
+			continue
+		}
+
+		children := s.sanitizeNodes(element.Children)
+
+		if _, ok := s.Tags[element.Tag]; !ok {
+			// we remove the tag but keep its content
+			sanitized = append(sanitized, children...)
+			continue
+		}
+
+		sanitized = append(sanitized, &HTMLElement{
+			Tag:        element.Tag,
+			Void:       element.Void,
+			Attributes: s.sanitizeAttributes(element.Tag, element.Attributes),
+			Children:   children,
+		})
+	}
+
+	return sanitized
+}
+
+// Parses untrusted HTML and removes everything that isn't allowed by the
+// policy. If the HTML can't be parsed, we return it as escaped text.
+func (s *SanitizePolicy) Sanitize(source string) Element {
+
+	parser := &Parser{}
+	nodes, err := parser.ParseHTML(source)
+
+	if err != nil {
+		Log.Warning("Cannot parse HTML for sanitizing: %v", err)
+		return Literal(source)
+	}
+
+	return F(s.sanitizeNodes(nodes)...)
+}
+
+func Sanitize(source string, policy *SanitizePolicy) Element {
+	return policy.Sanitize(source)
+}
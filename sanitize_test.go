@@ -0,0 +1,59 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package gospel
+
+import (
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+
+	for _, test := range []struct {
+		policy   *SanitizePolicy
+		source   string
+		expected string
+	}{
+		{StrictTextPolicy, `<b>bold</b> &amp; <i>italic</i>`, `bold &amp; italic`},
+		{BasicFormattingPolicy, `<p>One<p>Two <B onclick="x()">bold</b>`, `<p>One</p><p>Two <b>bold</b></p>`},
+		{BasicFormattingPolicy, `<script>alert(1)</script><style>a{}</style>text`, `text`},
+		{BasicFormattingPolicy, `<ul><li>a<li>b</ul>`, `<ul><li>a</li><li>b</li></ul>`},
+		{BasicFormattingPolicy, `a<br>b<!-- comment -->`, `a<br/>b`},
+		{UGCPolicy, `<a href="javascript:alert(1)" target=_blank>x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{UGCPolicy, `<a href='https://example.com/?a=1&amp;b=2' title=Example>x</a>`, `<a href="https://example.com/?a=1&amp;b=2" title="Example" rel="nofollow noopener noreferrer">x</a>`},
+		{UGCPolicy, `<img src="data:image/png;base64,xx" alt="x"><img src=/a.png alt="&lt;a&gt;">`, `<img alt="x"/><img src="/a.png" alt="&lt;a&gt;"/>`},
+		{UGCPolicy, `<table><tr><td colspan=2 style="color:red">a<td>b</table>`, `<table><tr><td colspan="2">a</td><td>b</td></tr></table>`},
+		{UGCPolicy, `<div><span>unclosed`, `<div><span>unclosed</span></div>`},
+		{UGCPolicy, `<iframe src="https://evil"></iframe><unknown>kept</unknown>`, `kept`},
+		{UGCPolicy, `<p title="a" hidden>x</p>`, `<p>x</p>`},
+	} {
+		if result := test.policy.Sanitize(test.source).RenderElement(); result != test.expected {
+			t.Errorf("%s: expected '%s', got '%s'", test.source, test.expected, result)
+		}
+	}
+}
+
+func TestParseHTML(t *testing.T) {
+
+	parser := &Parser{}
+	nodes, err := parser.ParseHTML(`<!DOCTYPE html><html><body><input type="checkbox" checked disabled/><textarea>&lt;b&gt;</textarea></body></html>`)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result := F(nodes...).RenderElement(); result != `<html><body><input type="checkbox" checked disabled/><textarea>&lt;b&gt;</textarea></body></html>` {
+		t.Fatalf("unexpected result: %s", result)
+	}
+}
@@ -15,6 +15,7 @@
 package gospel
 
 import (
+	"context"
 	"crypto/md5"
 	"encoding/hex"
 	"fmt"
@@ -30,6 +31,7 @@ type Server struct {
 	server     *http.Server
 	fileServer http.Handler
 	app        *App
+	memoCache  *MemoCache
 }
 
 type PrefixFS struct {
@@ -63,6 +65,14 @@ func (m *MultiFS) Open(name string) (fs.File, error) {
 
 func MakeServer(app *App) *Server {
 
+	if app.State == nil {
+		app.State = MakeAppState()
+	}
+
+	if app.Services == nil {
+		app.Services = MakeServices()
+	}
+
 	fs := &PrefixFS{
 		fs:     &MultiFS{append([]fs.FS{JS}, app.StaticFiles...)},
 		prefix: app.StaticPrefix,
@@ -71,6 +81,7 @@ func MakeServer(app *App) *Server {
 	return &Server{
 		app:        app,
 		fs:         fs,
+		memoCache:  MakeMemoCache(app.MemoCacheSize),
 		fileServer: http.FileServer(http.FS(fs)),
 		server: &http.Server{
 			Addr: ":8001",
@@ -117,20 +128,53 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if s.app.RenderTimeout > 0 {
+		// element functions can observe the deadline via the context
+		renderCtx, cancel := context.WithTimeout(r.Context(), s.app.RenderTimeout)
+		defer cancel()
+		r = r.WithContext(renderCtx)
+	}
+
 	// we make a persistent store for the session
 	persistentStore := makeCookieStore(r)
 	store := MakeStore(persistentStore)
+	// we close per-request services when we're done
+	defer store.Cleanup()
+	// memoized values are shared between requests
+	store.Memo = s.memoCache
 	ctx := MakeDefaultContext(r, w, store)
+	ctx.app = s.app
+
+	if s.app.RateLimiter != nil {
+		if allowed, wait := s.app.RateLimiter.Allow(ctx); !allowed {
+			TooManyRequests(ctx, wait)
+			http.Error(w, "too many requests", http.StatusTooManyRequests)
+			return
+		}
+	}
 
 	// we set up the router (it adds itself to the context)...
 	router := MakeRouter(ctx)
 
 	elem := ctx.Execute(s.app.Root)
 
+	// the page might have been rendered only partially, so we don't store
+	// the session (and discard its changes) instead of persisting a state
+	// that the user has never seen
+	switch r.Context().Err() {
+	case context.Canceled:
+		// the client has disconnected, there's no one to respond to
+		return
+	case context.DeadlineExceeded:
+		Log.Warning("Rendering '%s' exceeded the timeout of %v, discarding session changes", r.URL.Path, s.app.RenderTimeout)
+		http.Error(w, "rendering timed out", http.StatusServiceUnavailable)
+		return
+	}
+
 	store.Finalize()
 	persistentStore.Finalize(w)
 
-	if redirectedTo := router.RedirectedTo(); redirectedTo != "" && (redirectedTo != r.URL.Path || r.Method != http.MethodGet) {
+	if redirectedTo, ok := router.Redirect(); ok {
 		http.Redirect(w, r, redirectedTo, 302)
 		return
 	}
@@ -140,12 +184,22 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	renderedElement := elem.RenderElement()
+	renderedElement := RenderWith(elem, s.app.RenderOptions)
 
 	w.Header().Add("content-type", "text/html")
 	w.WriteHeader(ctx.StatusCode())
 	w.Write([]byte(renderedElement))
 
+	// we deliver the shell right away and stream suspended elements
+	// as soon as they're resolved
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
+	if err := store.WriteSuspended(r.Context(), w); err != nil {
+		Log.Warning("Cannot stream suspended elements of '%s': %v", r.URL.Path, err)
+	}
+
 }
 
 func (s *Server) Start() error {
@@ -156,4 +210,5 @@ func (s *Server) Start() error {
 
 func (s *Server) Stop() {
 	// to do: implement stop
+	s.app.Services.Close()
 }
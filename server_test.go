@@ -0,0 +1,105 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package gospel
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderTimeout(t *testing.T) {
+
+	var delay time.Duration
+
+	server := MakeServer(&App{
+		StaticPrefix:  "/static",
+		RenderTimeout: 20 * time.Millisecond,
+		Root: func(c Context) Element {
+
+			PersistentGlobalVar(c, "visited", false).Set(true)
+
+			select {
+			case <-time.After(delay):
+			case <-c.Ctx().Done():
+			}
+
+			return Div("page")
+		},
+	})
+
+	request := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+		return w
+	}
+
+	w := request()
+
+	if w.Code != http.StatusOK || w.Body.String() != "<div>page</div>" {
+		t.Fatalf("expected the page, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if len(w.Result().Cookies()) == 0 {
+		t.Fatalf("expected the session to be stored")
+	}
+
+	delay = time.Second
+	w = request()
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected a 503, got %d", w.Code)
+	}
+
+	// the changes of the session are discarded
+	if len(w.Result().Cookies()) != 0 {
+		t.Fatalf("expected no session cookie, got %v", w.Result().Cookies())
+	}
+}
+
+func TestSuspenseStreaming(t *testing.T) {
+
+	server := MakeServer(&App{
+		StaticPrefix: "/static",
+		Root: func(c Context) Element {
+			return Div(
+				Suspense(c, Span("loading"), func(c Context) Element {
+					// the element is still running when the page is finalized
+					select {
+					case <-time.After(50 * time.Millisecond):
+						return Span("slow")
+					case <-c.Ctx().Done():
+						return nil
+					}
+				}),
+			)
+		},
+	})
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	body := w.Body.String()
+
+	if !strings.HasPrefix(body, `<div><div id="root.suspense.0" style="display: contents"><span>loading</span></div></div>`) {
+		t.Fatalf("expected the fallback first, got %s", body)
+	}
+
+	if !strings.Contains(body, `<template data-gospel-suspense="root.suspense.0"><span>slow</span></template>`) {
+		t.Fatalf("expected the resolved element to be streamed, got %s", body)
+	}
+}
@@ -0,0 +1,310 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package gospel
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+)
+
+type Lifetime int
+
+const (
+	// one instance for the whole app
+	Singleton Lifetime = iota
+	// one instance per request, closed when the request is done
+	PerRequest
+	// a new instance every time the service is used
+	Transient
+)
+
+type provider struct {
+	mutex    sync.Mutex
+	lifetime Lifetime
+	factory  func(c Context) (any, error)
+	instance any
+	created  bool
+	// closed when the goroutine creating the singleton is done
+	creating chan struct{}
+	// the resolution creating the singleton (guarded by Services.waiting)
+	creator *resolution
+}
+
+// A chain of singleton creations on one goroutine, e.g. a factory that
+// resolves another singleton. We track which provider it is waiting for
+// to detect cycles across goroutines, which would deadlock otherwise.
+type resolution struct {
+	waitingFor *provider
+}
+
+// A registry of service providers. Services are identified by their type.
+type Services struct {
+	mutex     sync.RWMutex
+	providers map[reflect.Type]*provider
+	// guards the creators of the providers and what resolutions wait for
+	waiting sync.Mutex
+}
+
+func MakeServices() *Services {
+	return &Services{
+		providers: make(map[reflect.Type]*provider),
+	}
+}
+
+func (s *Services) provider(t reflect.Type) *provider {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.providers[t]
+}
+
+func (s *Services) register(t reflect.Type, p *provider) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.providers[t] = p
+}
+
+// Closes all singleton instances
+func (s *Services) Close() {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, p := range s.providers {
+		p.mutex.Lock()
+		if p.created {
+			closeService(p.instance)
+			p.instance = nil
+			p.created = false
+		}
+		p.mutex.Unlock()
+	}
+}
+
+func closeService(instance any) {
+	switch closer := instance.(type) {
+	case io.Closer:
+		if err := closer.Close(); err != nil {
+			Log.Error("Cannot close service of type %T: %v", instance, err)
+		}
+	case interface{ Close() }:
+		closer.Close()
+	}
+}
+
+// The context of singleton factories. It doesn't belong to a request, as
+// singletons outlive it, and it knows which singletons are being created.
+type singletonContext struct {
+	*DefaultContext
+	resolution *resolution
+	resolving  []reflect.Type
+}
+
+func (s *singletonContext) Service(t reflect.Type) (any, error) {
+	return s.App().Services.Resolve(s, s.Store, t)
+}
+
+// Returns an instance of the service with the given type
+func (s *Services) Resolve(c Context, store *Store, t reflect.Type) (any, error) {
+
+	p := s.provider(t)
+
+	if p == nil {
+		return nil, fmt.Errorf("no provider for type %s", t)
+	}
+
+	if sc, ok := c.(*singletonContext); ok && p.lifetime != Singleton {
+		// the singleton would keep the instance beyond its lifetime
+		return nil, fmt.Errorf("cannot use service %s in the singleton %s", t, sc.resolving[len(sc.resolving)-1])
+	}
+
+	switch p.lifetime {
+	case Singleton:
+		return s.singleton(c, p, t)
+	case PerRequest:
+
+		if instance, ok := store.service(t); ok {
+			return instance, nil
+		}
+
+		instance, err := p.factory(c)
+
+		if err != nil {
+			return nil, err
+		}
+
+		store.setService(t, instance)
+		store.OnCleanup(func() { closeService(instance) })
+
+		return instance, nil
+	}
+
+	instance, err := p.factory(c)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// transient instances are closed at the end of the request as well
+	store.OnCleanup(func() { closeService(instance) })
+
+	return instance, nil
+}
+
+// Returns the singleton instance, creating it if necessary. We don't hold
+// the lock while calling the factory, so that it can resolve other services.
+func (s *Services) singleton(c Context, p *provider, t reflect.Type) (any, error) {
+
+	r := &resolution{}
+	var resolving []reflect.Type
+
+	if sc, ok := c.(*singletonContext); ok {
+		r = sc.resolution
+		resolving = sc.resolving
+	}
+
+	for _, resolvingType := range resolving {
+		if resolvingType == t {
+			return nil, fmt.Errorf("cycle detected while creating the singleton %s", t)
+		}
+	}
+
+	p.mutex.Lock()
+
+	for !p.created && p.creating != nil {
+
+		// another goroutine is creating the instance, so we wait for it
+		creating := p.creating
+
+		if err := s.wait(r, p); err != nil {
+			p.mutex.Unlock()
+			return nil, fmt.Errorf("%w while creating the singleton %s", err, t)
+		}
+
+		p.mutex.Unlock()
+		<-creating
+		s.wait(r, nil)
+		p.mutex.Lock()
+	}
+
+	if p.created {
+		defer p.mutex.Unlock()
+		return p.instance, nil
+	}
+
+	creating := make(chan struct{})
+	p.creating = creating
+	s.setCreator(p, r)
+	p.mutex.Unlock()
+
+	var instance any
+	var err error
+	var done bool
+
+	defer func() {
+		p.mutex.Lock()
+		// we only keep successfully created instances (the factory might panic)
+		if done && err == nil {
+			p.instance = instance
+			p.created = true
+		}
+		p.creating = nil
+		s.setCreator(p, nil)
+		p.mutex.Unlock()
+		close(creating)
+	}()
+
+	appContext := MakeDefaultContext(nil, nil, MakeStore(MakeCookieStore("")))
+	appContext.app = c.App()
+
+	instance, err = p.factory(&singletonContext{
+		DefaultContext: appContext,
+		resolution:     r,
+		resolving:      append(resolving[:len(resolving):len(resolving)], t),
+	})
+	done = true
+
+	return instance, err
+}
+
+func (s *Services) setCreator(p *provider, r *resolution) {
+	s.waiting.Lock()
+	defer s.waiting.Unlock()
+	p.creator = r
+}
+
+// Records that the resolution waits for the provider (or for nothing). If the
+// creator of the provider waits for the resolution itself (maybe through other
+// resolutions), we would never wake up, so we return an error instead.
+func (s *Services) wait(r *resolution, p *provider) error {
+
+	s.waiting.Lock()
+	defer s.waiting.Unlock()
+
+	for q := p; q != nil && q.creator != nil; q = q.creator.waitingFor {
+		if q.creator == r {
+			return fmt.Errorf("cycle detected across goroutines")
+		}
+	}
+
+	r.waitingFor = p
+
+	return nil
+}
+
+// Registers a factory for services of type T. Singletons are created with
+// a context that doesn't belong to a request (so c.Request() returns nil)
+// and can only use other singletons.
+func Provide[T any](app *App, factory func(c Context) (T, error), lifetime Lifetime) {
+
+	if app.Services == nil {
+		app.Services = MakeServices()
+	}
+
+	app.Services.register(reflect.TypeOf((*T)(nil)).Elem(), &provider{
+		lifetime: lifetime,
+		factory: func(c Context) (any, error) {
+			return factory(c)
+		},
+	})
+}
+
+// Returns an instance of the service of type T
+func Resolve[T any](c Context) (T, error) {
+
+	instance, err := c.Service(reflect.TypeOf((*T)(nil)).Elem())
+
+	if err != nil {
+		return *new(T), err
+	}
+
+	if vt, ok := instance.(T); ok {
+		return vt, nil
+	}
+
+	return *new(T), fmt.Errorf("type error: %T vs. %T", instance, *new(T))
+}
+
+// Like Resolve, but logs the error and returns a zero value instead
+func Use[T any](c Context) T {
+
+	instance, err := Resolve[T](c)
+
+	if err != nil {
+		Log.Error("Cannot use service: %v", err)
+	}
+
+	return instance
+}
@@ -0,0 +1,227 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package gospel
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testService struct {
+	closed bool
+}
+
+func (t *testService) Close() {
+	t.closed = true
+}
+
+func TestServices(t *testing.T) {
+
+	app := &App{}
+
+	Provide(app, func(c Context) (*testService, error) { return &testService{}, nil }, PerRequest)
+	Provide(app, func(c Context) (int, error) { return 42, nil }, Singleton)
+
+	c := makeTestContextWithApp(app)
+
+	service := Use[*testService](c)
+
+	if service == nil || Use[*testService](c) != service {
+		t.Fatalf("expected the same instance within a request")
+	}
+
+	if Use[int](c) != 42 {
+		t.Fatalf("expected a singleton value")
+	}
+
+	if _, err := Resolve[string](c); err == nil {
+		t.Fatalf("expected an error for a missing provider")
+	}
+
+	c.Store.Cleanup()
+
+	if !service.closed {
+		t.Fatalf("expected the per-request instance to be closed")
+	}
+
+	if Use[*testService](makeTestContextWithApp(app)) == service {
+		t.Fatalf("expected a new instance for a new request")
+	}
+}
+
+type testConfig struct {
+	name string
+}
+
+type testRepository struct {
+	config *testConfig
+}
+
+type testCycle struct{}
+
+func TestSingletons(t *testing.T) {
+
+	app := &App{}
+
+	var creations atomic.Int64
+	var configErr error = fmt.Errorf("not ready")
+
+	Provide(app, func(c Context) (*testConfig, error) {
+		if c.Request() != nil || c.App() != app {
+			return nil, fmt.Errorf("expected an app-level context")
+		}
+		if configErr != nil {
+			return nil, configErr
+		}
+		creations.Add(1)
+		// concurrent resolves wait for the instance
+		time.Sleep(10 * time.Millisecond)
+		return &testConfig{name: "test"}, nil
+	}, Singleton)
+
+	// resolving other singletons within a factory doesn't deadlock
+	Provide(app, func(c Context) (*testRepository, error) {
+		config, err := Resolve[*testConfig](c)
+		return &testRepository{config: config}, err
+	}, Singleton)
+
+	Provide(app, func(c Context) (*testCycle, error) {
+		return Resolve[*testCycle](c)
+	}, Singleton)
+
+	Provide(app, func(c Context) (int, error) {
+		// per-request services would outlive their request
+		_, err := Resolve[*testService](c)
+		return 0, err
+	}, Singleton)
+
+	Provide(app, func(c Context) (*testService, error) { return &testService{}, nil }, PerRequest)
+
+	// failed instances aren't kept
+	if _, err := Resolve[*testRepository](makeTestContextWithApp(app)); err == nil {
+		t.Fatalf("expected an error")
+	}
+
+	configErr = nil
+
+	var wg sync.WaitGroup
+	repositories := make([]*testRepository, 10)
+
+	for i := range repositories {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			repositories[i] = Use[*testRepository](makeTestContextWithApp(app))
+		}(i)
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("expected the singletons to be created")
+	}
+
+	for _, repository := range repositories {
+		if repository == nil || repository != repositories[0] || repository.config.name != "test" {
+			t.Fatalf("expected the same instance")
+		}
+	}
+
+	if creations.Load() != 1 {
+		t.Fatalf("expected one instance, got %d", creations.Load())
+	}
+
+	c := makeTestContextWithApp(app)
+
+	if _, err := Resolve[*testCycle](c); err == nil {
+		t.Fatalf("expected an error for a cycle")
+	}
+
+	if _, err := Resolve[int](c); err == nil {
+		t.Fatalf("expected an error for a per-request service in a singleton")
+	}
+}
+
+type testLeft struct {
+	right *testRight
+}
+
+type testRight struct {
+	left *testLeft
+}
+
+func TestConcurrentSingletonCycle(t *testing.T) {
+
+	app := &App{}
+
+	var started sync.WaitGroup
+	var leftStarted, rightStarted sync.Once
+
+	started.Add(2)
+
+	// both factories run at the same time, so each one waits for the other
+	Provide(app, func(c Context) (*testLeft, error) {
+		leftStarted.Do(started.Done)
+		started.Wait()
+		right, err := Resolve[*testRight](c)
+		return &testLeft{right: right}, err
+	}, Singleton)
+
+	Provide(app, func(c Context) (*testRight, error) {
+		rightStarted.Do(started.Done)
+		started.Wait()
+		left, err := Resolve[*testLeft](c)
+		return &testRight{left: left}, err
+	}, Singleton)
+
+	errs := make(chan error, 2)
+
+	go func() {
+		_, err := Resolve[*testLeft](makeTestContextWithApp(app))
+		errs <- err
+	}()
+
+	go func() {
+		_, err := Resolve[*testRight](makeTestContextWithApp(app))
+		errs <- err
+	}()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-errs:
+			if err == nil {
+				t.Fatalf("expected an error for a cycle")
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected the cycle to be detected")
+		}
+	}
+}
+
+func makeTestContextWithApp(app *App) *DefaultContext {
+	c := makeTestContext()
+	c.app = app
+	return c
+}
@@ -0,0 +1,204 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package gospel
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// Application-wide state that is shared between all requests. In contrast
+// to request variables, shared variables are safe for concurrent use.
+type AppState struct {
+	mutex     sync.Mutex
+	variables map[string]any
+}
+
+func MakeAppState() *AppState {
+	return &AppState{
+		variables: make(map[string]any),
+	}
+}
+
+// used for contexts that don't belong to an app (e.g. in tests)
+var defaultAppState = MakeAppState()
+
+func UseAppState(c Context) *AppState {
+	if app := c.App(); app != nil && app.State != nil {
+		return app.State
+	}
+	return defaultAppState
+}
+
+// Returns the variable with the given key, creating it if necessary
+func (a *AppState) get(key string, create func() any) any {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if variable, ok := a.variables[key]; ok {
+		return variable
+	}
+
+	variable := create()
+	a.variables[key] = variable
+	return variable
+}
+
+func (a *AppState) Delete(key string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	delete(a.variables, key)
+}
+
+func (a *AppState) Keys() []string {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	keys := make([]string, 0, len(a.variables))
+
+	for key := range a.variables {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+type sharedSubscription[T any] struct {
+	callback func(T)
+}
+
+type SharedVar[T any] struct {
+	mutex       sync.RWMutex
+	key         string
+	value       T
+	subscribers []*sharedSubscription[T]
+}
+
+func (s *SharedVar[T]) Key() string {
+	return s.key
+}
+
+func (s *SharedVar[T]) Get() T {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.value
+}
+
+func (s *SharedVar[T]) Set(value T) {
+	s.Update(func(T) T { return value })
+}
+
+// Atomically updates the value and notifies subscribers
+func (s *SharedVar[T]) Update(update func(T) T) T {
+	s.mutex.Lock()
+	s.value = update(s.value)
+	value := s.value
+	subscribers := make([]*sharedSubscription[T], len(s.subscribers))
+	copy(subscribers, s.subscribers)
+	s.mutex.Unlock()
+
+	// we call subscribers without holding the lock so they can access the variable
+	for _, sub := range subscribers {
+		sub.callback(value)
+	}
+
+	return value
+}
+
+// Subscribes to changes of the variable, returns a function that cancels
+// the subscription again. Callbacks can be called from any goroutine.
+func (s *SharedVar[T]) Subscribe(callback func(T)) func() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	sub := &sharedSubscription[T]{callback}
+	s.subscribers = append(s.subscribers, sub)
+
+	return func() {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		for i, existingSub := range s.subscribers {
+			if existingSub == sub {
+				s.subscribers = append(s.subscribers[:i], s.subscribers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// A lock-free shared counter
+type SharedCounter struct {
+	key   string
+	value atomic.Int64
+}
+
+func (s *SharedCounter) Key() string {
+	return s.key
+}
+
+func (s *SharedCounter) Get() int64 {
+	return s.value.Load()
+}
+
+func (s *SharedCounter) Set(value int64) {
+	s.value.Store(value)
+}
+
+func (s *SharedCounter) Add(delta int64) int64 {
+	return s.value.Add(delta)
+}
+
+// Returns the shared variable with the given key from the app state, or
+// creates it with the given value if it doesn't exist yet. Returns an error
+// if the key is used by a variable of another type.
+func SharedState[T any](state *AppState, key string, value T) (*SharedVar[T], error) {
+
+	if key == "" {
+		return nil, fmt.Errorf("empty key")
+	}
+
+	variable := state.get(key, func() any {
+		return &SharedVar[T]{key: key, value: value}
+	})
+
+	if vt, ok := variable.(*SharedVar[T]); ok {
+		return vt, nil
+	}
+
+	return nil, fmt.Errorf("type error: shared variable '%s' has type %T", key, variable)
+}
+
+func Shared[T any](c Context, key string, value T) (*SharedVar[T], error) {
+	return SharedState(UseAppState(c), key, value)
+}
+
+func Counter(c Context, key string) (*SharedCounter, error) {
+
+	if key == "" {
+		return nil, fmt.Errorf("empty key")
+	}
+
+	variable := UseAppState(c).get(key, func() any {
+		return &SharedCounter{key: key}
+	})
+
+	if vt, ok := variable.(*SharedCounter); ok {
+		return vt, nil
+	}
+
+	return nil, fmt.Errorf("type error: shared variable '%s' has type %T", key, variable)
+}
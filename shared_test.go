@@ -0,0 +1,141 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package gospel
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func makeSharedTestContext() *DefaultContext {
+	return makeTestContextWithApp(&App{State: MakeAppState()})
+}
+
+func TestSharedVar(t *testing.T) {
+
+	c := makeSharedTestContext()
+
+	var notifications atomic.Int64
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			// all goroutines get the same variable
+			counter, err := Shared(c, "counter", 0)
+
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			unsubscribe := counter.Subscribe(func(int) { notifications.Add(1) })
+			defer unsubscribe()
+
+			for j := 0; j < 100; j++ {
+				counter.Update(func(value int) int { return value + 1 })
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	counter, err := Shared(c, "counter", 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if counter.Get() != 1000 {
+		t.Fatalf("expected 1000, got %d", counter.Get())
+	}
+
+	// each update notifies at least the subscription of its own goroutine
+	if n := notifications.Load(); n < 1000 {
+		t.Fatalf("expected at least 1000 notifications, got %d", n)
+	}
+
+	notifications.Store(0)
+	counter.Set(0)
+
+	if notifications.Load() != 0 {
+		t.Fatalf("expected all subscriptions to be canceled")
+	}
+}
+
+func TestSharedCounter(t *testing.T) {
+
+	c := makeSharedTestContext()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			counter, err := Counter(c, "visits")
+
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			for j := 0; j < 100; j++ {
+				counter.Add(1)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	counter, err := Counter(c, "visits")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if counter.Get() != 1000 {
+		t.Fatalf("expected 1000, got %d", counter.Get())
+	}
+}
+
+func TestSharedTypeErrors(t *testing.T) {
+
+	c := makeSharedTestContext()
+
+	if _, err := Shared(c, "value", "text"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Shared(c, "value", 1); err == nil {
+		t.Fatalf("expected a type error")
+	}
+
+	if _, err := Counter(c, "value"); err == nil {
+		t.Fatalf("expected a type error")
+	}
+
+	if _, err := Shared(c, "", 1); err == nil {
+		t.Fatalf("expected an error for an empty key")
+	}
+
+	if keys := UseAppState(c).Keys(); len(keys) != 1 || keys[0] != "value" {
+		t.Fatalf("unexpected keys: %v", keys)
+	}
+}
@@ -15,12 +15,15 @@
 package gospel
 
 import (
-	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
 )
 
+// Variables can be used by asynchronous elements, so we protect their
+// value, subscribers and dependents
 type VarObj[T any] struct {
+	mutex       sync.Mutex
 	context     Context
 	value       T
 	generator   func() T
@@ -29,19 +32,81 @@ type VarObj[T any] struct {
 	persistent  bool
 	initialized bool
 	clear       bool
-	onUpdate    func()
+	subscribers []*subscription
+	codec       Codec
+	version     int
+	migrate     MigrationFunc[T]
+	// for computed variables
+	compute    func() T
+	stale      bool
+	computing  bool
+	dependents []ContextVarObj
 }
 
 func MakeVarObj[T any](context Context, generator func() T) *VarObj[T] {
+	codec, version, migrate := lookupTypeCodec[T]()
 	return &VarObj[T]{
 		context:   context,
 		generator: generator,
 		id:        "",
+		codec:     codec,
+		version:   version,
+		migrate:   migrate,
 	}
 }
 
-func (s *VarObj[T]) OnUpdate(onUpdate func()) {
-	s.onUpdate = onUpdate
+type subscription struct {
+	callback func()
+}
+
+// Subscribes to changes of the variable, returns a function that cancels
+// the subscription again
+func (s *VarObj[T]) Subscribe(callback func()) func() {
+
+	if s.copy {
+		return s.context.GetById(s.id).Subscribe(callback)
+	}
+
+	sub := &subscription{callback}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.subscribers = append(s.subscribers, sub)
+
+	return func() {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		for i, existingSub := range s.subscribers {
+			if existingSub == sub {
+				s.subscribers = append(s.subscribers[:i], s.subscribers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Calls all subscribers of the variable
+func (s *VarObj[T]) Notify() {
+
+	// subscribers might unsubscribe while we notify them
+	s.mutex.Lock()
+	subscribers := make([]*subscription, len(s.subscribers))
+	copy(subscribers, s.subscribers)
+	s.mutex.Unlock()
+
+	for _, sub := range subscribers {
+		sub.callback()
+	}
+}
+
+func (s *VarObj[T]) notify() {
+	if s.context != nil {
+		// the context decides whether to notify now or at the end of a batch
+		s.context.Notify(s)
+	} else {
+		s.Notify()
+	}
 }
 
 func (s *VarObj[T]) SetCopy(copy bool) {
@@ -61,31 +126,220 @@ func (s *VarObj[T]) Id() string {
 }
 
 func (s *VarObj[T]) Get() T {
+	if s.context != nil {
+		// we record the read so we know which elements depend on this variable
+		s.context.Track(s.id)
+	}
 	if s.copy {
 		if vt, ok := s.context.GetById(s.id).GetRaw().(T); ok {
 			return vt
 		}
 		return *new(T)
 	}
+	s.mutex.Lock()
+	stale := s.compute != nil && s.stale
+	s.mutex.Unlock()
+
+	if stale {
+		s.recompute()
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	return s.value
 }
 
+func (s *VarObj[T]) recompute() {
+
+	s.mutex.Lock()
+
+	if s.computing {
+		s.mutex.Unlock()
+		// the computation (indirectly) depends on its own value
+		panic(fmt.Errorf("cycle detected while computing variable '%s'", s.id))
+	}
+
+	s.computing = true
+	s.mutex.Unlock()
+
+	// we don't hold the lock while computing, as the computation reads
+	// other variables
+	defer func() {
+		s.mutex.Lock()
+		s.computing = false
+		s.mutex.Unlock()
+	}()
+
+	value := s.compute()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.value = value
+	s.stale = false
+	s.initialized = true
+}
+
 func (s *VarObj[T]) Reset() {
+	if s.compute != nil {
+		s.Invalidate()
+		return
+	}
 	s.Set(s.generator())
 }
 
+func (s *VarObj[T]) Computed() bool {
+	return s.compute != nil
+}
+
+func (s *VarObj[T]) Dependents() []ContextVarObj {
+	if s.copy {
+		return s.context.GetById(s.id).Dependents()
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]ContextVarObj(nil), s.dependents...)
+}
+
+// Registers a variable that will be invalidated when this variable changes
+func (s *VarObj[T]) AddDependent(dependent ContextVarObj) error {
+
+	if s.copy {
+		return s.context.GetById(s.id).AddDependent(dependent)
+	}
+
+	// if this variable can be reached from the dependent, we'd create a cycle
+	if dependsOn(s, dependent) {
+		return fmt.Errorf("cycle detected: variable '%s' depends on '%s'", s.id, dependent.Id())
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.dependents = append(s.dependents, dependent)
+
+	return nil
+}
+
+func dependsOn(variable, on ContextVarObj) bool {
+
+	if variable == on {
+		return true
+	}
+
+	for _, dependent := range on.Dependents() {
+		if dependsOn(variable, dependent) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Marks a computed variable as stale and invalidates all its dependents
+func (s *VarObj[T]) Invalidate() {
+
+	if s.copy {
+		s.context.GetById(s.id).Invalidate()
+		return
+	}
+
+	if s.compute != nil {
+
+		s.mutex.Lock()
+		stale := s.stale
+		s.stale = true
+		s.mutex.Unlock()
+
+		if stale {
+			// we've already been invalidated
+			return
+		}
+
+		if s.context != nil {
+			s.context.Invalidate(s.id)
+		}
+
+		s.notify()
+	}
+
+	s.invalidateDependents()
+}
+
+func (s *VarObj[T]) invalidateDependents() {
+
+	s.mutex.Lock()
+	dependents := s.dependents
+	s.mutex.Unlock()
+
+	for _, dependent := range dependents {
+		dependent.Invalidate()
+	}
+}
+
+func (s *VarObj[T]) SetCodec(codec Codec) {
+	s.codec = codec
+}
+
+func (s *VarObj[T]) Codec() Codec {
+	return s.codec
+}
+
+func (s *VarObj[T]) Version() int {
+	return s.version
+}
+
 func (s *VarObj[T]) Serialize() ([]byte, error) {
-	return json.Marshal(s.value)
+
+	s.mutex.Lock()
+	value := s.value
+	s.mutex.Unlock()
+
+	if data, err := s.codec.Encode(value); err != nil {
+		return nil, err
+	} else {
+		return encodeEnvelope(s.codec.Name(), s.version, data), nil
+	}
 }
 
 func (s *VarObj[T]) Deserialize(data []byte) error {
 
+	codecName, version, payload, err := decodeEnvelope(data)
+
+	if err != nil {
+		return err
+	}
+
+	// the data comes from the client, so we don't let it choose the codec:
+	// we only accept the codec of the variable and JSON (for older values)
+	var codec Codec
+
+	switch codecName {
+	case s.codec.Name():
+		codec = s.codec
+	case JSONCodec.Name():
+		codec = JSONCodec
+	default:
+		return fmt.Errorf("codec '%s' not allowed for variable '%s'", codecName, s.id)
+	}
+
 	nv := *new(T)
 
-	if err := json.Unmarshal(data, &nv); err != nil {
+	if version != s.version {
+		if s.migrate == nil {
+			return fmt.Errorf("cannot migrate value from version %d to %d", version, s.version)
+		}
+		if nv, err = s.migrate(version, payload, codec); err != nil {
+			return fmt.Errorf("cannot migrate value from version %d: %w", version, err)
+		}
+	} else if err := codec.Decode(payload, &nv); err != nil {
 		return err
 	}
 
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	s.value = nv
 	s.initialized = true
 
@@ -114,6 +368,8 @@ func (s *VarObj[T]) SetPersistent(value bool) {
 }
 
 func (s *VarObj[T]) Initialized() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 	return s.initialized
 }
 
@@ -128,18 +384,29 @@ func (s *VarObj[T]) ScopedId() string {
 }
 
 func (s *VarObj[T]) Set(value any) error {
+	if s.compute != nil {
+		return fmt.Errorf("variable '%s' is computed and can't be set", s.id)
+	}
 	if s.copy {
-		s.context.SetById(s.id, value)
+		if err := s.context.SetById(s.id, value); err != nil {
+			return err
+		}
 	} else if sv, ok := value.(T); ok {
+		s.mutex.Lock()
 		s.value = sv
 		s.initialized = true
+		s.mutex.Unlock()
+		if s.context != nil {
+			s.context.Invalidate(s.id)
+		}
+		s.invalidateDependents()
 	} else if value != nil {
 		Log.Error("type error: %T vs. %T", value, *new(T))
 		return fmt.Errorf("type error")
 	}
 
-	if s.onUpdate != nil {
-		s.onUpdate()
+	if !s.copy {
+		s.notify()
 	}
 
 	return nil
@@ -148,12 +415,19 @@ func (s *VarObj[T]) Set(value any) error {
 type ContextVarObj interface {
 	Serialize() ([]byte, error)
 	Deserialize([]byte) error
+	SetCodec(Codec)
+	Codec() Codec
+	Version() int
 	SetPersistent(bool)
 	Persistent() bool
 	Initialized() bool
 	Context() Context
 	ScopedId() string
-	OnUpdate(func())
+	Subscribe(func()) func()
+	Notify()
+	AddDependent(ContextVarObj) error
+	Dependents() []ContextVarObj
+	Invalidate()
 	SetId(string)
 	Id() string
 	SetCopy(bool)
@@ -243,6 +517,28 @@ func CachedVar[T any](c Context, value func() T) *VarObj[T] {
 	return sv
 }
 
+// Creates a read-only variable whose value is computed from the given
+// dependencies. The value is recomputed lazily after a dependency changes.
+func Computed[T any](c Context, compute func() T, deps ...ContextVarObj) *VarObj[T] {
+	sv := MakeVarObj[T](c, compute)
+	sv.compute = compute
+	sv.stale = true
+	c.AddVar(sv, "")
+
+	// the original variable is already registered with its dependencies
+	if sv.IsCopy() {
+		return sv
+	}
+
+	for _, dep := range deps {
+		if err := dep.AddDependent(sv); err != nil {
+			panic(err)
+		}
+	}
+
+	return sv
+}
+
 func Var[T any](c Context, value T) *VarObj[T] {
 	return CachedVar(c, func() T { return value })
 }
@@ -272,6 +568,12 @@ func GlobalVar[T any](c Context, key string, v T) *VarObj[T] {
 	return variable
 }
 
+// Executes the function and notifies subscribers only once per changed
+// variable, after the function returns
+func Batch(c Context, f func()) {
+	c.Batch(f)
+}
+
 func UseGlobal[T any](c Context, key string) T {
 
 	variable := c.GetVar(key)
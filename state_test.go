@@ -0,0 +1,127 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package gospel
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func makeTestContext() *DefaultContext {
+	r := httptest.NewRequest("GET", "/", nil)
+	return MakeDefaultContext(r, httptest.NewRecorder(), MakeStore(MakeCookieStore("")))
+}
+
+func TestComputed(t *testing.T) {
+
+	c := makeTestContext()
+
+	a := Var(c, 2)
+	b := Var(c, 3)
+
+	calls := 0
+
+	sum := Computed(c, func() int {
+		calls++
+		return a.Get() + b.Get()
+	}, a, b)
+
+	double := Computed(c, func() int { return sum.Get() * 2 }, sum)
+
+	if double.Get() != 10 || sum.Get() != 5 || calls != 1 {
+		t.Fatalf("unexpected values: %d, %d (%d calls)", double.Get(), sum.Get(), calls)
+	}
+
+	a.Set(5)
+
+	// the value is only recomputed on access
+	if calls != 1 {
+		t.Fatalf("expected lazy recomputation")
+	}
+
+	if double.Get() != 16 || calls != 2 {
+		t.Fatalf("unexpected value: %d (%d calls)", double.Get(), calls)
+	}
+
+	if err := sum.Set(4); err == nil {
+		t.Fatalf("expected an error when setting a computed variable")
+	}
+}
+
+func TestComputedCycles(t *testing.T) {
+
+	c := makeTestContext()
+
+	a := Var(c, 1)
+	b := Computed(c, func() int { return a.Get() }, a)
+	d := Computed(c, func() int { return b.Get() }, b)
+
+	if err := d.AddDependent(b); err == nil {
+		t.Fatalf("expected a cycle error")
+	}
+
+	var e *VarObj[int]
+
+	e = Computed(c, func() int { return e.Get() + 1 })
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected a panic")
+		}
+	}()
+
+	e.Get()
+}
+
+func TestSubscriptionsAndBatching(t *testing.T) {
+
+	c := makeTestContext()
+
+	a := Var(c, 1)
+	b := Var(c, "foo")
+
+	first, second := 0, 0
+
+	unsubscribe := a.Subscribe(func() { first++ })
+	a.Subscribe(func() { second++ })
+	b.Subscribe(func() { second++ })
+
+	a.Set(2)
+
+	if first != 1 || second != 1 {
+		t.Fatalf("expected both subscribers to be notified: %d, %d", first, second)
+	}
+
+	Batch(c, func() {
+		a.Set(3)
+		a.Set(4)
+		b.Set("bar")
+
+		if first != 1 {
+			t.Fatalf("expected notifications to be deferred")
+		}
+	})
+
+	if first != 2 || second != 3 {
+		t.Fatalf("expected one notification per variable: %d, %d", first, second)
+	}
+
+	unsubscribe()
+	a.Set(5)
+
+	if first != 2 || second != 4 {
+		t.Fatalf("expected only the second subscriber to be notified: %d, %d", first, second)
+	}
+}
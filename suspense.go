@@ -0,0 +1,108 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package gospel
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// swaps resolved templates into their placeholders, retrying until nothing
+// changes so that nested placeholders are resolved regardless of the order
+const suspenseScript = `for(var d=1;d;){d=0;document.querySelectorAll("template[data-gospel-suspense]").forEach(function(t){var p=document.getElementById(t.getAttribute("data-gospel-suspense"));if(p){p.replaceWith(t.content);t.remove();d=1}})}document.currentScript.remove()`
+
+type suspendedElement struct {
+	id      string
+	element Element
+}
+
+// Renders the fallback in place of the element until the element function
+// is done. The resolved element is streamed at the end of the document and
+// swapped into place by a small inline script.
+func Suspense(c Context, fallback Element, elementFunction ElementFunction) Element {
+
+	i := c.NextIndex(c.Key() + ".suspense")
+	id := fmt.Sprintf("%s.suspense.%d", c.Key(), i)
+
+	c.Suspend(id, c.AsyncElement(fmt.Sprintf("suspense.%d", i), elementFunction))
+
+	return Div(
+		Id(id),
+		Style("display: contents"),
+		fallback,
+	)
+}
+
+// Waits for the element in a separate goroutine and queues the result
+func (s *Store) Suspend(ctx context.Context, id string, await PureElementFunction) {
+
+	s.mutex.Lock()
+	s.suspended++
+
+	if s.resolved == nil {
+		s.resolved = make(chan *suspendedElement)
+	}
+
+	resolved := s.resolved
+	s.mutex.Unlock()
+
+	go func() {
+		element := await()
+		select {
+		case resolved <- &suspendedElement{id, element}:
+		case <-ctx.Done():
+			// no one is waiting for the element anymore
+		}
+	}()
+}
+
+// Writes suspended elements in the order in which they are resolved,
+// flushing the writer after each one if possible
+func (s *Store) WriteSuspended(ctx context.Context, w io.Writer) error {
+
+	s.mutex.Lock()
+	n := s.suspended
+	resolved := s.resolved
+	s.suspended = 0
+	s.mutex.Unlock()
+
+	for i := 0; i < n; i++ {
+		select {
+		case se := <-resolved:
+
+			if se.element == nil {
+				// we keep the fallback
+				continue
+			}
+
+			swap := Template(DataAttrib("gospel-suspense", se.id), se.element).RenderElement() + Script(suspenseScript).RenderElement()
+
+			if _, err := w.Write([]byte(swap)); err != nil {
+				return err
+			}
+
+			if flusher, ok := w.(http.Flusher); ok {
+				flusher.Flush()
+			}
+
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,172 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package svg
+
+import (
+	"github.com/gospel-sh/gospel"
+)
+
+// SVG attributes are case-sensitive, so we keep their camelCase names
+// https://www.w3.org/TR/SVG2/attindex.html
+
+var attrib = gospel.Attrib
+
+// Core attributes
+
+var Id = attrib("id")
+var Class = attrib("class")
+var Style = attrib("style")
+var Lang = attrib("lang")
+var Tabindex = attrib("tabindex")
+var Role = attrib("role")
+
+// Links
+
+var Href = attrib("href")
+var XLinkHref = attrib("xlink:href") // for SVG 1.1 user agents
+var Target = attrib("target")
+
+// Geometry
+
+var ViewBox = attrib("viewBox")
+var PreserveAspectRatio = attrib("preserveAspectRatio")
+var Width = attrib("width")
+var Height = attrib("height")
+var X = attrib("x")
+var Y = attrib("y")
+var X1 = attrib("x1")
+var Y1 = attrib("y1")
+var X2 = attrib("x2")
+var Y2 = attrib("y2")
+var Cx = attrib("cx")
+var Cy = attrib("cy")
+var R = attrib("r")
+var Rx = attrib("rx")
+var Ry = attrib("ry")
+var D = attrib("d")
+var Points = attrib("points")
+var PathLength = attrib("pathLength")
+var Transform = attrib("transform")
+var TransformOrigin = attrib("transform-origin")
+
+// Presentation attributes
+
+var Fill = attrib("fill")
+var FillOpacity = attrib("fill-opacity")
+var FillRule = attrib("fill-rule")
+var Stroke = attrib("stroke")
+var StrokeWidth = attrib("stroke-width")
+var StrokeLinecap = attrib("stroke-linecap")
+var StrokeLinejoin = attrib("stroke-linejoin")
+var StrokeMiterlimit = attrib("stroke-miterlimit")
+var StrokeDasharray = attrib("stroke-dasharray")
+var StrokeDashoffset = attrib("stroke-dashoffset")
+var StrokeOpacity = attrib("stroke-opacity")
+var Opacity = attrib("opacity")
+var Color = attrib("color")
+var Display = attrib("display")
+var Visibility = attrib("visibility")
+var Overflow = attrib("overflow")
+var ClipPathAttr = attrib("clip-path")
+var ClipRule = attrib("clip-rule")
+var MaskAttr = attrib("mask")
+var FilterAttr = attrib("filter")
+var MarkerStart = attrib("marker-start")
+var MarkerMid = attrib("marker-mid")
+var MarkerEnd = attrib("marker-end")
+var VectorEffect = attrib("vector-effect")
+var ShapeRendering = attrib("shape-rendering")
+var PointerEvents = attrib("pointer-events")
+var StopColor = attrib("stop-color")
+var StopOpacity = attrib("stop-opacity")
+var FloodColor = attrib("flood-color")
+var FloodOpacity = attrib("flood-opacity")
+var LightingColor = attrib("lighting-color")
+
+// Text
+
+var FontFamily = attrib("font-family")
+var FontSize = attrib("font-size")
+var FontWeight = attrib("font-weight")
+var FontStyle = attrib("font-style")
+var TextAnchor = attrib("text-anchor")
+var DominantBaseline = attrib("dominant-baseline")
+var Dx = attrib("dx")
+var Dy = attrib("dy")
+var Rotate = attrib("rotate")
+var TextLength = attrib("textLength")
+var LengthAdjust = attrib("lengthAdjust")
+var StartOffset = attrib("startOffset")
+var PathAttr = attrib("path")
+
+// Gradients, patterns, markers, masks and clip paths
+
+var Offset = attrib("offset")
+var GradientUnits = attrib("gradientUnits")
+var GradientTransform = attrib("gradientTransform")
+var SpreadMethod = attrib("spreadMethod")
+var Fx = attrib("fx")
+var Fy = attrib("fy")
+var Fr = attrib("fr")
+var PatternUnits = attrib("patternUnits")
+var PatternContentUnits = attrib("patternContentUnits")
+var PatternTransform = attrib("patternTransform")
+var MarkerUnits = attrib("markerUnits")
+var MarkerWidth = attrib("markerWidth")
+var MarkerHeight = attrib("markerHeight")
+var RefX = attrib("refX")
+var RefY = attrib("refY")
+var Orient = attrib("orient")
+var MaskUnits = attrib("maskUnits")
+var MaskContentUnits = attrib("maskContentUnits")
+var ClipPathUnits = attrib("clipPathUnits")
+
+// Filters
+
+var FilterUnits = attrib("filterUnits")
+var PrimitiveUnits = attrib("primitiveUnits")
+var In = attrib("in")
+var In2 = attrib("in2")
+var Result = attrib("result")
+var StdDeviation = attrib("stdDeviation")
+var Mode = attrib("mode")
+var Operator = attrib("operator")
+var Values = attrib("values")
+var Type = attrib("type")
+var K1 = attrib("k1")
+var K2 = attrib("k2")
+var K3 = attrib("k3")
+var K4 = attrib("k4")
+var Scale = attrib("scale")
+var BaseFrequency = attrib("baseFrequency")
+var NumOctaves = attrib("numOctaves")
+var Seed = attrib("seed")
+
+// Animations
+
+var AttributeName = attrib("attributeName")
+var Begin = attrib("begin")
+var End = attrib("end")
+var Dur = attrib("dur")
+var From = attrib("from")
+var To = attrib("to")
+var By = attrib("by")
+var RepeatCount = attrib("repeatCount")
+var RepeatDur = attrib("repeatDur")
+var KeyTimes = attrib("keyTimes")
+var KeySplines = attrib("keySplines")
+var CalcMode = attrib("calcMode")
+var Additive = attrib("additive")
+var Accumulate = attrib("accumulate")
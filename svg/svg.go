@@ -0,0 +1,98 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+// SVG elements and attributes. The names of tags and attributes follow the
+// conventions of the gospel package, but as many of them clash with HTML
+// names, the package is meant to be imported with its name, e.g.
+//
+//	svg.Svg(svg.ViewBox("0 0 24 24"), svg.Path(svg.D("M12 2L2 22h20z")))
+package svg
+
+import (
+	"github.com/gospel-sh/gospel"
+)
+
+func Tag(name string, decorators ...gospel.HTMLElementDecorator) func(args ...any) *gospel.HTMLElement {
+	return gospel.ForeignTag(gospel.SVGNamespace, name, decorators...)
+}
+
+// SVG Tags
+// https://www.w3.org/TR/SVG2/eltindex.html
+
+// the root element declares the namespace, which is required for
+// standalone SVG documents
+var Svg = Tag("svg", gospel.DeclareNamespace())
+
+var A = Tag("a")
+var Animate = Tag("animate")
+var AnimateMotion = Tag("animateMotion")
+var AnimateTransform = Tag("animateTransform")
+var Circle = Tag("circle")
+var ClipPath = Tag("clipPath")
+var Defs = Tag("defs")
+var Desc = Tag("desc")
+var Ellipse = Tag("ellipse")
+var FeBlend = Tag("feBlend")
+var FeColorMatrix = Tag("feColorMatrix")
+var FeComponentTransfer = Tag("feComponentTransfer")
+var FeComposite = Tag("feComposite")
+var FeConvolveMatrix = Tag("feConvolveMatrix")
+var FeDiffuseLighting = Tag("feDiffuseLighting")
+var FeDisplacementMap = Tag("feDisplacementMap")
+var FeDistantLight = Tag("feDistantLight")
+var FeDropShadow = Tag("feDropShadow")
+var FeFlood = Tag("feFlood")
+var FeFuncA = Tag("feFuncA")
+var FeFuncB = Tag("feFuncB")
+var FeFuncG = Tag("feFuncG")
+var FeFuncR = Tag("feFuncR")
+var FeGaussianBlur = Tag("feGaussianBlur")
+var FeImage = Tag("feImage")
+var FeMerge = Tag("feMerge")
+var FeMergeNode = Tag("feMergeNode")
+var FeMorphology = Tag("feMorphology")
+var FeOffset = Tag("feOffset")
+var FePointLight = Tag("fePointLight")
+var FeSpecularLighting = Tag("feSpecularLighting")
+var FeSpotLight = Tag("feSpotLight")
+var FeTile = Tag("feTile")
+var FeTurbulence = Tag("feTurbulence")
+var Filter = Tag("filter")
+var ForeignObject = Tag("foreignObject")
+var G = Tag("g")
+var Image = Tag("image")
+var Line = Tag("line")
+var LinearGradient = Tag("linearGradient")
+var Marker = Tag("marker")
+var Mask = Tag("mask")
+var Metadata = Tag("metadata")
+var Mpath = Tag("mpath")
+var Path = Tag("path")
+var Pattern = Tag("pattern")
+var Polygon = Tag("polygon")
+var Polyline = Tag("polyline")
+var RadialGradient = Tag("radialGradient")
+var Rect = Tag("rect")
+var Script = Tag("script")
+var Set = Tag("set")
+var Stop = Tag("stop")
+var StyleTag = Tag("style") // renamed as it clashes with the 'Style' attribute
+var Switch = Tag("switch")
+var Symbol = Tag("symbol")
+var Text = Tag("text")
+var TextPath = Tag("textPath")
+var Title = Tag("title")
+var Tspan = Tag("tspan")
+var Use = Tag("use")
+var View = Tag("view")
@@ -0,0 +1,50 @@
+// Gospel - Golang Simple Extensible Web Framework
+// Copyright (C) 2019-2024 - The Gospel Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the 3-Clause BSD License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// license for more details.
+//
+// You should have received a copy of the 3-Clause BSD License
+// along with this program.  If not, see <https://opensource.org/licenses/BSD-3-Clause>.
+
+package svg_test
+
+import (
+	"github.com/gospel-sh/gospel"
+	. "github.com/gospel-sh/gospel/svg"
+	"testing"
+)
+
+func TestRendering(t *testing.T) {
+
+	icon := gospel.Span(
+		gospel.Class("icon"),
+		Svg(
+			ViewBox("0 0 24 24"),
+			Defs(LinearGradient(Id("fade"), Stop(Offset("0%"), StopColor("#fff")))),
+			Path(D("M12 2L2 22h20z"), Fill("url(#fade)"), StrokeLinecap("round")),
+			Use(Href("#shape"), XLinkHref("javascript:alert(1)")),
+			Text(X(4), Y(20), "a < b"),
+		),
+	)
+
+	expected := `<span class="icon"><svg viewBox="0 0 24 24" xmlns="http://www.w3.org/2000/svg">` +
+		`<defs><linearGradient id="fade"><stop offset="0%" stop-color="#fff"/></linearGradient></defs>` +
+		`<path d="M12 2L2 22h20z" fill="url(#fade)" stroke-linecap="round"/>` +
+		`<use href="#shape" xlink:href="about:invalid#zGospelz"/>` +
+		`<text x="4" y="20">a &lt; b</text></svg></span>`
+
+	if html := icon.RenderElement(); html != expected {
+		t.Fatalf("unexpected output:\n%s\n%s", html, expected)
+	}
+
+	// an explicit namespace declaration isn't duplicated
+	if html := Svg(gospel.Attrib("xmlns")(gospel.SVGNamespace)).RenderElement(); html != `<svg xmlns="http://www.w3.org/2000/svg"/>` {
+		t.Fatalf("unexpected output: %s", html)
+	}
+}
@@ -14,6 +14,20 @@
 
 package gospel
 
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// Returns a random hex ID with 128 bits of entropy
+func RandomId() (string, error) {
+	data := make([]byte, 16)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(data), nil
+}
+
 func If[T any](condition bool, value T) T {
 	if condition {
 		return value